@@ -0,0 +1,80 @@
+package babyapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithAbsoluteURLs makes the Location header and HATEOAS links absolute URLs. If baseURL is set, like
+// "https://api.example.com", it is used for all URLs. Otherwise, the scheme and host are read from the
+// X-Forwarded-Proto and X-Forwarded-Host headers, falling back to the request's TLS state and Host. Since clients can
+// set these headers, only use an empty baseURL behind a proxy that overwrites them. This applies to nested APIs too
+func (a *API[T]) WithAbsoluteURLs(baseURL string) *API[T] {
+	a.panicIfReadOnly()
+
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			a.errors = append(a.errors, fmt.Errorf("WithAbsoluteURLs: invalid base URL: %w", err))
+			return a
+		}
+		if u.Scheme == "" || u.Host == "" {
+			a.errors = append(a.errors, fmt.Errorf("WithAbsoluteURLs: base URL %q must have a scheme and host", baseURL))
+			return a
+		}
+	}
+
+	a.absoluteURLs = true
+	a.absoluteURLBase = strings.TrimSuffix(baseURL, "/")
+	return a
+}
+
+func (a *API[T]) absoluteURLMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := a.absoluteURLBase
+		if base == "" {
+			base = requestBaseURL(r)
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), absoluteURLCtxKey, base)))
+	})
+}
+
+// requestBaseURL gets the scheme and host that the client used for the request, including forwarded headers
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+
+	host := r.Host
+	if forwardedHost := firstHeaderValue(r, "X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+
+	return scheme + "://" + host
+}
+
+// firstHeaderValue gets the first value from a header that might have a comma-separated list, which is added by each
+// proxy that the request passes through
+func firstHeaderValue(r *http.Request, key string) string {
+	value, _, _ := strings.Cut(r.Header.Get(key), ",")
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// AbsoluteURL returns an absolute URL for the path when WithAbsoluteURLs is used. Otherwise, the path is returned
+// unchanged. This can be used for custom links from a Linker
+func AbsoluteURL(r *http.Request, path string) string {
+	base, ok := r.Context().Value(absoluteURLCtxKey).(string)
+	if !ok || base == "" {
+		return path
+	}
+
+	return base + path
+}
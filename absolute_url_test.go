@@ -0,0 +1,111 @@
+package babyapi_test
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsoluteURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		headers  map[string]string
+		useTLS   bool
+		expected string
+	}{
+		{"Relative", "", nil, false, "/albums/"},
+		{"BaseURL", "https://api.example.com/", map[string]string{"X-Forwarded-Host": "ignored.example.com"}, false, "https://api.example.com/albums/"},
+		{"RequestHost", "", nil, false, "http://example.com/albums/"},
+		{"TLS", "", nil, true, "https://example.com/albums/"},
+		{
+			"ForwardedHeaders",
+			"",
+			map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Host": "public.example.com, proxy.internal"},
+			false,
+			"https://public.example.com/albums/",
+		},
+		{"InvalidForwardedProto", "", map[string]string{"X-Forwarded-Proto": "javascript"}, false, "http://example.com/albums/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).WithHATEOAS()
+			if tt.name != "Relative" {
+				api.WithAbsoluteURLs(tt.baseURL)
+			}
+
+			router, err := api.Router()
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"New Album"}`))
+			r.Header.Set("Content-Type", "application/json")
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			if tt.useTLS {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+			var resp struct {
+				ID    string            `json:"id"`
+				Links map[string]string `json:"_links"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.expected+resp.ID, w.Result().Header.Get("Location"))
+			require.Equal(t, tt.expected+resp.ID, resp.Links["self"])
+			require.Equal(t, strings.TrimSuffix(tt.expected, "/"), resp.Links["collection"])
+		})
+	}
+
+	t.Run("NestedAPI", func(t *testing.T) {
+		artistAPI := babyapi.NewAPI("Artists", "/artists", func() *Artist { return &Artist{} }).
+			WithAbsoluteURLs("https://api.example.com")
+		albumAPI := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+		artistAPI.AddNestedAPI(albumAPI)
+
+		router, err := artistAPI.Router()
+		require.NoError(t, err)
+
+		post := func(path, body string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			return w
+		}
+
+		w := post("/artists", `{"name":"Artist"}`)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+		var artist Artist
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &artist))
+
+		w = post("/artists/"+artist.GetID()+"/albums", `{"title":"Album"}`)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+		var album Album
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &album))
+		require.Equal(t, "https://api.example.com/artists/"+artist.GetID()+"/albums/"+album.GetID(), w.Result().Header.Get("Location"))
+	})
+
+	t.Run("InvalidBaseURL", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithAbsoluteURLs("example.com")
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, `WithAbsoluteURLs: base URL "example.com" must have a scheme and host`)
+	})
+}
@@ -0,0 +1,136 @@
+package babyapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessLogFormat is the format of access logs written by WithAccessLogFormat
+type AccessLogFormat string
+
+const (
+	// AccessLogCommon is the Common Log Format: host ident authuser [date] "request" status bytes
+	AccessLogCommon AccessLogFormat = "common"
+	// AccessLogCombined is the Combined Log Format, which adds the "referer" and "user-agent" to the Common Log Format
+	AccessLogCombined AccessLogFormat = "combined"
+	// AccessLogJSON writes each access log as a JSON object
+	AccessLogJSON AccessLogFormat = "json"
+)
+
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+type accessLogger struct {
+	format AccessLogFormat
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithAccessLogFormat writes an access log line in the format to the writer for each request. If the writer is nil,
+// os.Stdout is used. Access logs are written alongside the structured "response completed" logs, which can be
+// disabled using the slog level. This only applies to the root-level API
+func (a *API[T]) WithAccessLogFormat(format AccessLogFormat, w io.Writer) *API[T] {
+	a.panicIfReadOnly()
+
+	switch format {
+	case AccessLogCommon, AccessLogCombined, AccessLogJSON:
+	default:
+		a.errors = append(a.errors, fmt.Errorf("WithAccessLogFormat: unsupported format %q", format))
+		return a
+	}
+
+	if w == nil {
+		w = os.Stdout
+	}
+
+	a.accessLog = &accessLogger{format: format, w: w}
+	return a
+}
+
+// write formats and writes the access log for the completed request
+func (l *accessLogger) write(r *http.Request, received time.Time, status, bytesWritten int, elapsed time.Duration) error {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	var line []byte
+	switch l.format {
+	case AccessLogJSON:
+		data, err := json.Marshal(map[string]any{
+			"time":        received.Format(time.RFC3339),
+			"remote_addr": remoteHost(r),
+			"user":        requestUser(r),
+			"method":      r.Method,
+			"path":        r.RequestURI,
+			"proto":       r.Proto,
+			"status":      status,
+			"bytes":       bytesWritten,
+			"duration_ms": float64(elapsed.Microseconds()) / 1000,
+			"referer":     r.Referer(),
+			"user_agent":  r.UserAgent(),
+			"request_id":  middleware.GetReqID(r.Context()),
+		})
+		if err != nil {
+			return err
+		}
+		line = append(data, '\n')
+	default:
+		user := dashIfEmpty(requestUser(r))
+
+		size := "-"
+		if bytesWritten > 0 {
+			size = strconv.Itoa(bytesWritten)
+		}
+
+		line = fmt.Appendf(nil, "%s - %s [%s] %q %d %s",
+			remoteHost(r),
+			user,
+			received.Format(clfTimeFormat),
+			fmt.Sprintf("%s %s %s", r.Method, r.RequestURI, r.Proto),
+			status,
+			size,
+		)
+		if l.format == AccessLogCombined {
+			line = fmt.Appendf(line, " %q %q", dashIfEmpty(r.Referer()), dashIfEmpty(r.UserAgent()))
+		}
+		line = append(line, '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := l.w.Write(line)
+	return err
+}
+
+// dashIfEmpty returns "-" for missing values like the Common and Combined Log Formats expect
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// remoteHost gets the client's address without the port
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// requestUser gets the username from basic auth, if it is used
+func requestUser(r *http.Request) string {
+	user, _, _ := r.BasicAuth()
+	return user
+}
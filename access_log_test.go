@@ -0,0 +1,123 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogFormat(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		format   babyapi.AccessLogFormat
+		expected string
+	}{
+		{
+			"Common",
+			babyapi.AccessLogCommon,
+			`192.0.2.1 - user [02/Jan/2024:03:04:05 +0000] "GET /albums?q=1 HTTP/1.1" 200 %d` + "\n",
+		},
+		{
+			"Combined",
+			babyapi.AccessLogCombined,
+			`192.0.2.1 - user [02/Jan/2024:03:04:05 +0000] "GET /albums?q=1 HTTP/1.1" 200 %d "http://example.com" "test-agent"` + "\n",
+		},
+	}
+
+	request := func(t *testing.T, format babyapi.AccessLogFormat, buf *bytes.Buffer) *httptest.ResponseRecorder {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithClock(func() time.Time { return now }).
+			WithAccessLogFormat(format, buf)
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/albums?q=1", http.NoBody)
+		r.SetBasicAuth("user", "password")
+		r.Header.Set("Referer", "http://example.com")
+		r.Header.Set("User-Agent", "test-agent")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		return w
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := request(t, tt.format, &buf)
+			require.Equal(t, fmt.Sprintf(tt.expected, w.Body.Len()), buf.String())
+		})
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := request(t, babyapi.AccessLogJSON, &buf)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		require.Equal(t, "2024-01-02T03:04:05Z", entry["time"])
+		require.Equal(t, "192.0.2.1", entry["remote_addr"])
+		require.Equal(t, "user", entry["user"])
+		require.Equal(t, "GET", entry["method"])
+		require.Equal(t, "/albums?q=1", entry["path"])
+		require.EqualValues(t, 200, entry["status"])
+		require.EqualValues(t, w.Body.Len(), entry["bytes"])
+		require.Equal(t, "test-agent", entry["user_agent"])
+		require.NotEmpty(t, entry["request_id"])
+	})
+
+	t.Run("CombinedMissingValues", func(t *testing.T) {
+		var buf bytes.Buffer
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithClock(func() time.Time { return now }).
+			WithAccessLogFormat(babyapi.AccessLogCombined, &buf)
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		r.Header.Del("User-Agent")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, fmt.Sprintf(`192.0.2.1 - - [02/Jan/2024:03:04:05 +0000] "GET /albums HTTP/1.1" 200 %d "-" "-"`+"\n", w.Body.Len()), buf.String())
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		var buf bytes.Buffer
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithClock(func() time.Time { return now }).
+			WithAccessLogFormat(babyapi.AccessLogCommon, &buf).
+			AddCustomRoute(http.MethodGet, "/panic", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				panic("oops")
+			}))
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums/panic", http.NoBody))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.Equal(t, `192.0.2.1 - - [02/Jan/2024:03:04:05 +0000] "GET /albums/panic HTTP/1.1" 500 -`+"\n", buf.String())
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithAccessLogFormat("xml", nil)
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, `WithAccessLogFormat: unsupported format "xml"`)
+	})
+}
@@ -0,0 +1,160 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/go-chi/render"
+)
+
+// AggregatingStorage can be implemented by a Storage that is able to count resources by a field natively. It is used
+// by the aggregate endpoint instead of reading all resources. The query contains the request's query parameters so
+// the Storage is responsible for applying any filters. It is not used when FilterableFields or the GetAll filter
+// apply to the request since they can only be applied to resources read from storage
+type AggregatingStorage interface {
+	Aggregate(ctx context.Context, query url.Values, field string) (map[string]int, error)
+}
+
+// AggregateResult is the response from the aggregate endpoint. It maps each group_by field to the number of
+// resources with each value
+type AggregateResult map[string]map[string]int
+
+// Render implements render.Renderer
+func (AggregateResult) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// WithAggregation adds a GET /base/aggregate route that counts resources grouped by the fields in the group_by query
+// parameter. Fields are referenced by their JSON names and only the provided fields can be used. The same filters as
+// GetAll are applied before counting. If the Storage implements AggregatingStorage, it is used instead when no
+// filters apply to the request
+func (a *API[T]) WithAggregation(fields ...string) *API[T] {
+	a.panicIfReadOnly()
+
+	if a.instance == nil {
+		a.errors = append(a.errors, fmt.Errorf("WithAggregation: cannot be used with a root API"))
+		return a
+	}
+
+	t := reflect.TypeOf(a.instance())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		a.errors = append(a.errors, fmt.Errorf("WithAggregation: unable to get fields from resource type %s", t))
+		return a
+	}
+
+	jsonFields := getJSONFields(t)
+	for _, field := range fields {
+		if !slices.ContainsFunc(jsonFields, func(f jsonField) bool { return f.name == field }) {
+			a.errors = append(a.errors, fmt.Errorf("WithAggregation: unknown field %q", field))
+			return a
+		}
+	}
+
+	a.aggregationFields = append(a.aggregationFields, fields...)
+	return a
+}
+
+func (a *API[T]) defaultAggregate() http.HandlerFunc {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		logger := GetLoggerFromContext(r.Context())
+
+		groupBy := r.URL.Query()["group_by"]
+		if len(groupBy) == 0 {
+			return ErrInvalidRequest(errors.New("missing group_by query parameter"))
+		}
+
+		for _, field := range groupBy {
+			if !slices.Contains(a.aggregationFields, field) {
+				return ErrInvalidRequest(fmt.Errorf("unsupported group_by field %q", field))
+			}
+		}
+
+		filter, err := a.getAllFilterFunc(r)
+		if err != nil {
+			logger.Error("error parsing filters", "error", err)
+			return ErrInvalidRequest(err)
+		}
+
+		result := AggregateResult{}
+
+		aggregator, ok := a.getReadStorage().(AggregatingStorage)
+		if ok && filter == nil {
+			for _, field := range groupBy {
+				counts, err := aggregator.Aggregate(r.Context(), r.URL.Query(), field)
+				if err != nil {
+					logger.Error("error aggregating resources", "error", err)
+					return InternalServerError(err)
+				}
+				result[field] = counts
+			}
+
+			return result
+		}
+
+		resources, err := a.getReadStorage().GetAll(r.Context(), r.URL.Query())
+		if err != nil {
+			logger.Error("error getting resources", "error", err)
+			return InternalServerError(err)
+		}
+
+		resources = filter.Filter(resources)
+
+		for _, field := range groupBy {
+			counts := map[string]int{}
+			for _, resource := range resources {
+				counts[groupValue(resource, field)]++
+			}
+			result[field] = counts
+		}
+
+		return result
+	})
+}
+
+// groupValue uses reflection to get the string representation of a resource's field by its JSON name. Missing or
+// nil values are grouped as "null"
+func groupValue(resource any, field string) string {
+	const null = "null"
+
+	v := reflect.ValueOf(resource)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return null
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return null
+	}
+
+	for _, f := range getJSONFields(v.Type()) {
+		if f.name != field {
+			continue
+		}
+
+		fieldValue, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			return null
+		}
+
+		for fieldValue.Kind() == reflect.Pointer || fieldValue.Kind() == reflect.Interface {
+			if fieldValue.IsNil() {
+				return null
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		return fmt.Sprint(fieldValue.Interface())
+	}
+
+	return null
+}
@@ -0,0 +1,135 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type Task struct {
+	babyapi.DefaultResource
+	Status   string  `json:"status"`
+	Priority int     `json:"priority"`
+	Owner    *string `json:"owner"`
+}
+
+type aggregatingStorage struct {
+	babyapi.Storage[*Task]
+}
+
+func (aggregatingStorage) Aggregate(_ context.Context, _ url.Values, field string) (map[string]int, error) {
+	return map[string]int{"native": 1}, nil
+}
+
+func TestAggregation(t *testing.T) {
+	owner := "owner1"
+	tasks := []*Task{
+		{DefaultResource: babyapi.NewDefaultResource(), Status: "active", Priority: 1, Owner: &owner},
+		{DefaultResource: babyapi.NewDefaultResource(), Status: "active", Priority: 2},
+		{DefaultResource: babyapi.NewDefaultResource(), Status: "closed", Priority: 1},
+	}
+
+	newAPI := func() *babyapi.API[*Task] {
+		api := babyapi.NewAPI("Tasks", "/tasks", func() *Task { return &Task{} }).
+			WithAggregation("status", "priority", "owner").
+			SetFilterableFields(babyapi.FilterableField[*Task]{Name: "status", Value: func(t *Task) string { return t.Status }})
+		for _, task := range tasks {
+			require.NoError(t, api.Storage.Set(context.Background(), task))
+		}
+		return api
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			"GroupByStatus",
+			"group_by=status",
+			http.StatusOK,
+			`{"status":{"active":2,"closed":1}}`,
+		},
+		{
+			"GroupByMultiple",
+			"group_by=priority&group_by=owner",
+			http.StatusOK,
+			`{"priority":{"1":2,"2":1},"owner":{"owner1":1,"null":2}}`,
+		},
+		{
+			"WithFilter",
+			"group_by=priority&status=active",
+			http.StatusOK,
+			`{"priority":{"1":1,"2":1}}`,
+		},
+		{
+			"MissingGroupBy",
+			"",
+			http.StatusBadRequest,
+			`{"status":"Invalid request.","error_code":"invalid_request","error":"missing group_by query parameter"}`,
+		},
+		{
+			"UnsupportedField",
+			"group_by=id",
+			http.StatusBadRequest,
+			`{"status":"Invalid request.","error_code":"invalid_request","error":"unsupported group_by field \"id\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/tasks/aggregate?"+tt.query, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Task](t, newAPI(), r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+
+	t.Run("AggregatingStorage", func(t *testing.T) {
+		api := babyapi.NewAPI("Tasks", "/tasks", func() *Task { return &Task{} }).
+			WithAggregation("status").
+			SetStorage(aggregatingStorage{babyapi.NewKVStorage[*Task](kv.NewDefaultDB(), "Tasks")})
+
+		r, err := http.NewRequest(http.MethodGet, "/tasks/aggregate?group_by=status", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Task](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"status":{"native":1}}`, w.Body.String())
+	})
+
+	t.Run("AggregatingStorageWithGetAllFilter", func(t *testing.T) {
+		api := babyapi.NewAPI("Tasks", "/tasks", func() *Task { return &Task{} }).
+			WithAggregation("status").
+			SetStorage(aggregatingStorage{babyapi.NewKVStorage[*Task](kv.NewDefaultDB(), "Tasks")}).
+			SetGetAllFilter(func(*http.Request) babyapi.FilterFunc[*Task] {
+				return func(t *Task) bool { return t.Priority == 1 }
+			})
+		for _, task := range tasks {
+			require.NoError(t, api.Storage.Set(context.Background(), task))
+		}
+
+		r, err := http.NewRequest(http.MethodGet, "/tasks/aggregate?group_by=status", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Task](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"status":{"active":1,"closed":1}}`, w.Body.String())
+	})
+	t.Run("UnknownField", func(t *testing.T) {
+		api := babyapi.NewAPI("Tasks", "/tasks", func() *Task { return &Task{} }).
+			WithAggregation("status", "missing")
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, `WithAggregation: unknown field "missing"`)
+	})
+}
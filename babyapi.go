@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"log"
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/calvinmclean/babyapi/storage/kv"
@@ -31,6 +33,10 @@ type API[T Resource] struct {
 	// Storage is the interface used by the API server to read/write resources
 	Storage[T]
 
+	// readStorage and writeStorage optionally override Storage for the default handlers' reads and writes
+	readStorage  Storage[T]
+	writeStorage Storage[T]
+
 	// context is set by WithContext to allow external goroutines to control API shutdown
 	context context.Context
 
@@ -40,6 +46,12 @@ type API[T Resource] struct {
 	// shutdown is used so the Stop() method can block until the API is fully shutdown
 	shutdown chan struct{}
 
+	// shutdownDeadline is the Unix time in nanoseconds when Serve will stop waiting for requests to finish
+	shutdownDeadline atomic.Int64
+
+	maintenance           atomic.Bool
+	maintenanceBlockReads bool
+
 	// instance is currently required for PUT because render.Bind() requires a non-nil input for T. Since
 	// I need to have pointer receivers on Bind and Render implementations, `new(T)` creates a nil instance
 	instance func() T
@@ -53,13 +65,75 @@ type API[T Resource] struct {
 	responseWrapper       func(T) render.Renderer
 	getAllResponseWrapper func([]T) render.Renderer
 
-	getAllFilter func(*http.Request) FilterFunc[T]
+	computedFields     func(context.Context, T) map[string]any
+	computedFieldNames []string
+
+	collectionHTMLTemplate *template.Template
+
+	getAllFilter        func(*http.Request) FilterFunc[T]
+	filterableFields    []FilterableField[T]
+	repeatedParamPolicy RepeatedParamPolicy
+
+	bulkCreate     bool
+	strictBinding  bool
+	importExport   bool
+	fieldSelection bool
+	schemaEndpoint bool
+	allowEmptyBody bool
+
+	strictContentType bool
+	consistencyHeader bool
+
+	aggregationFields []string
+
+	defaultSort string
+
+	examples []T
+
+	openAPIVersion string
+
+	storageKeyFunc func(*http.Request, string) string
+	idGenerator    idGenerator
+	idType         func(string) error
+
+	locker         Locker
+	readCoalescing *flightGroup[T]
+
+	maxInFlight int
+	retryAfter  time.Duration
+
+	methodTimeouts map[string]time.Duration
+
+	timeFormat         string
+	fieldNameTransform func(string) string
+	jsonCodec          JSONCodec
+
+	absoluteURLs    bool
+	absoluteURLBase string
+
+	etags              bool
+	devMode            bool
+	serverTime         bool
+	goneForSoftDeleted bool
+	hateoas            bool
+
+	now func() time.Time
+
+	accessLog *accessLogger
+
+	eventBackpressure eventBackpressure
+
+	changeEventListeners []func(*http.Request, ChangeEvent[T])
+	eventIncludePrevious bool
 
 	beforeDelete beforeAfterFunc
 	afterDelete  beforeAfterFunc
 
+	cascadeParentID func(T) string
+
 	onCreateOrUpdate    func(http.ResponseWriter, *http.Request, T) *ErrResponse
 	afterCreateOrUpdate func(http.ResponseWriter, *http.Request, T) *ErrResponse
+	postWriteHookPolicy PostWriteHookPolicy
 
 	parent relatedAPI
 
@@ -102,20 +176,67 @@ func NewAPI[T Resource](name, base string, instance func() T) *API[T] {
 		nil,
 		nil,
 		NewKVStorage[T](kv.NewDefaultDB(), name),
+		nil,
+		nil,
 		context.Background(),
 		make(chan struct{}, 1),
 		make(chan struct{}, 1),
+		atomic.Int64{},
+		atomic.Bool{},
+		false,
 		instance,
 		nil,
 		nil,
 		nil,
 		func(r T) render.Renderer { return r },
 		nil,
+		nil,
+		nil,
+		nil,
 		func(*http.Request) FilterFunc[T] { return nil },
+		nil,
+		RepeatedParamIn,
+		false,
+		false,
+		false,
+		false,
+		false,
+		false,
+		false,
+		false,
+		nil,
+		"",
+		nil,
+		"",
+		nil,
+		idGenerator{},
+		nil,
+		nil,
+		nil,
+		0,
+		0,
+		map[string]time.Duration{},
+		"",
+		nil,
+		nil,
+		false,
+		"",
+		false,
+		false,
+		false,
+		false,
+		false,
+		time.Now,
+		nil,
+		eventBackpressure{},
+		nil,
+		false,
 		defaultBeforeAfter,
 		defaultBeforeAfter,
+		nil,
 		func(http.ResponseWriter, *http.Request, T) *ErrResponse { return nil },
 		func(http.ResponseWriter, *http.Request, T) *ErrResponse { return nil },
+		PostWriteHookFail,
 		nil,
 		defaultResponseCodes(),
 		nil,
@@ -167,6 +288,15 @@ func (a *API[T]) Name() string {
 	return a.name
 }
 
+// WithBasePath overrides the base path that was set in NewAPI. The API's name is still used for the ID URL parameter
+// and logging, so this can be used to change URLs without changing anything else
+func (a *API[T]) WithBasePath(base string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.base = base
+	return a
+}
+
 // SetCustomResponseCode will override the default response codes for the specified HTTP verb. Use MethodGetAll to set the
 // response code for listing all resources
 func (a *API[T]) SetCustomResponseCode(verb string, code int) *API[T] {
@@ -185,6 +315,16 @@ func (a *API[T]) SetGetAllResponseWrapper(getAllResponder func([]T) render.Rende
 	return a
 }
 
+// WithCollectionHTMLTemplate sets the template used to wrap the HTML of each item when the default GetAll handler
+// responds with HTML. The template is executed with ResourceListHTML, which has the rendered item HTML and some
+// additional metadata about the list. It does not apply if a custom GetAll response wrapper is used
+func (a *API[T]) WithCollectionHTMLTemplate(tmpl *template.Template) *API[T] {
+	a.panicIfReadOnly()
+
+	a.collectionHTMLTemplate = tmpl
+	return a
+}
+
 // SetOnCreateOrUpdate runs on POST, PATCH, and PUT requests before saving the created/updated resource.
 // This is useful for adding more validations or performing tasks related to resources such as initializing
 // schedules or sending events
@@ -294,7 +434,8 @@ func (a *API[T]) AddCustomRoute(method, pattern string, handler http.Handler) *A
 }
 
 // AddCustomIDRoute appends a custom API route to the base path after the ID URL parameter: /base/{ID}/custom-route.
-// The handler for this route can access the requested resource using GetResourceFromContext
+// The handler for this route can access the requested resource using GetResourceFromContext. If the pattern has
+// additional URL parameters, like /sub/{subID}, use GetPathParam to read them
 func (a *API[T]) AddCustomIDRoute(method, pattern string, handler http.Handler) *API[T] {
 	a.panicIfReadOnly()
 
@@ -356,7 +497,8 @@ func (a *API[T]) Serve(address string) error {
 			close(a.quit)
 		}
 
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		a.shutdownDeadline.Store(time.Now().Add(shutdownTimeout).UnixNano())
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer func() {
 			cancel()
 			close(a.shutdown)
@@ -419,6 +561,131 @@ func (a *API[T]) SetStorage(s Storage[T]) *API[T] {
 	return a
 }
 
+// WithStorageFailover sets the API's Storage to a FailoverStorage that uses the secondary storages when the primary
+// returns ErrStorageUnavailable
+func (a *API[T]) WithStorageFailover(primary Storage[T], secondary ...Storage[T]) *API[T] {
+	a.panicIfReadOnly()
+
+	a.Storage = NewFailoverStorage(primary, secondary...)
+	return a
+}
+
+// WithReadStorage sets a separate Storage used by the default handlers to read resources. This includes GET,
+// GetAll, and checking if a resource exists. If it is not set, the API's Storage is used
+func (a *API[T]) WithReadStorage(s Storage[T]) *API[T] {
+	a.panicIfReadOnly()
+
+	a.readStorage = s
+	return a
+}
+
+// WithWriteStorage sets a separate Storage used by the default handlers to create, update, and delete resources.
+// If it is not set, the API's Storage is used
+func (a *API[T]) WithWriteStorage(s Storage[T]) *API[T] {
+	a.panicIfReadOnly()
+
+	a.writeStorage = s
+	return a
+}
+
+// SetStorageKeyFunc sets a function used to create the key for reading and deleting a resource from storage using
+// the ID from the request URL. This is required when the resource implements StorageKeyer so the key matches the one
+// used to store resources. By default, the ID is used as the key
+func (a *API[T]) SetStorageKeyFunc(keyFunc func(*http.Request, string) string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.storageKeyFunc = keyFunc
+	return a
+}
+
+// storageKey gets the key used to read or delete a resource from storage
+func (a *API[T]) storageKey(r *http.Request, id string) string {
+	if a.storageKeyFunc == nil {
+		return id
+	}
+	return a.storageKeyFunc(r, id)
+}
+
+// validateStorageKey makes sure resources that are stored with a custom key can also be read and deleted using it
+func (a *API[T]) validateStorageKey() []error {
+	if a.instance == nil || a.storageKeyFunc != nil {
+		return nil
+	}
+
+	if _, ok := any(a.instance()).(StorageKeyer); ok {
+		return []error{fmt.Errorf("SetStorageKeyFunc: required because resource type %T implements StorageKeyer", a.instance())}
+	}
+
+	return nil
+}
+
+// getReadStorage returns the Storage used for reading resources
+func (a *API[T]) getReadStorage() Storage[T] {
+	if a.readStorage != nil {
+		return a.readStorage
+	}
+	return a.Storage
+}
+
+// getWriteStorage returns the Storage used for writing resources
+func (a *API[T]) getWriteStorage() Storage[T] {
+	if a.writeStorage != nil {
+		return a.writeStorage
+	}
+	return a.Storage
+}
+
+// WithAllowEmptyBody allows POST requests without a body to create a resource with default values instead of
+// responding with a bind error. The resource's Bind method is still called and defaults are applied if it implements
+// Defaultable
+func (a *API[T]) WithAllowEmptyBody() *API[T] {
+	a.panicIfReadOnly()
+
+	a.allowEmptyBody = true
+	return a
+}
+
+// WithStrictBinding will reject JSON request bodies that contain fields which don't exist in the resource. The
+// response is a 400 Bad Request with an error naming the unknown field. Fields with the `babyapi:"readonly"` tag are
+// also rejected instead of being ignored
+func (a *API[T]) WithStrictBinding() *API[T] {
+	a.panicIfReadOnly()
+
+	a.strictBinding = true
+	return a
+}
+
+// WithMaxInFlight limits the number of concurrent requests handled by the API. When the limit is reached, requests
+// are rejected with 503 Service Unavailable and a Retry-After header, which can be set with WithRetryAfter. The
+// /health and /metrics endpoints are not limited. This only applies to the root-level API
+func (a *API[T]) WithMaxInFlight(n int) *API[T] {
+	a.panicIfReadOnly()
+
+	a.maxInFlight = n
+	return a
+}
+
+// WithMethodTimeout sets a timeout for the request context of the API's default routes with the HTTP method. Use
+// MethodGetAll to set a separate timeout for GetAll, which otherwise uses the http.MethodGet timeout. HEAD requests
+// use the GET timeouts. The context is passed to Storage, so it is cancelled when the timeout is reached. Timeouts
+// are layered on any existing deadline, so they can only make it shorter. Since a parent resource is read for requests
+// to nested APIs, a parent's timeout also applies to its nested APIs
+func (a *API[T]) WithMethodTimeout(method string, timeout time.Duration) *API[T] {
+	a.panicIfReadOnly()
+
+	a.methodTimeouts[method] = timeout
+	return a
+}
+
+// WithDevMode includes more details in error responses to help with debugging, such as the reason a response could
+// not be serialized. This should not be used in production since it can expose internal details
+func (a *API[T]) WithDevMode() *API[T] {
+	a.panicIfReadOnly()
+
+	a.devMode = true
+	return a
+}
+
 // WithContext adds a context to the API so that it will automatically shutdown when the context is closed
 func (a *API[T]) WithContext(ctx context.Context) *API[T] {
 	a.panicIfReadOnly()
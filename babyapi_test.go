@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
 	babytest "github.com/calvinmclean/babyapi/test"
 	"github.com/go-chi/render"
 	"github.com/rs/xid"
@@ -220,6 +223,886 @@ type Song struct {
 	Title string `json:"title"`
 }
 
+func TestReadWriteStorage(t *testing.T) {
+	readStorage := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+	writeStorage := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithReadStorage(readStorage).
+		WithWriteStorage(writeStorage)
+
+	client, stop := babytest.NewTestClient[*Album](t, api)
+	defer stop()
+
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	t.Run("WriteUsesWriteStorage", func(t *testing.T) {
+		_, err := client.Put(context.Background(), album)
+		require.NoError(t, err)
+
+		_, err = writeStorage.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+
+		_, err = readStorage.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("ReadUsesReadStorage", func(t *testing.T) {
+		_, err := client.Get(context.Background(), album.GetID())
+		require.Error(t, err)
+
+		require.NoError(t, readStorage.Set(context.Background(), album))
+
+		result, err := client.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		require.Equal(t, album, result.Data)
+
+		all, err := client.GetAll(context.Background(), "")
+		require.NoError(t, err)
+		require.Equal(t, []*Album{album}, all.Data.Items)
+	})
+
+	t.Run("ExistenceCheckUsesReadStorage", func(t *testing.T) {
+		other := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Other"}
+		require.NoError(t, writeStorage.Set(context.Background(), other))
+
+		_, err := client.Delete(context.Background(), other.GetID())
+		require.Error(t, err)
+	})
+
+	t.Run("DefaultsToStorage", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+
+		client, stop := babytest.NewTestClient[*Album](t, api)
+		defer stop()
+
+		_, err := client.Put(context.Background(), album)
+		require.NoError(t, err)
+
+		_, err = api.Storage.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+	})
+}
+
+func TestStrictBinding(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         bool
+		method         string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"UnknownFieldAllowedByDefault", false, http.MethodPost, `{"title": "Album1", "titel": "typo"}`, http.StatusCreated, ""},
+		{"UnknownFieldRejected", true, http.MethodPost, `{"title": "Album1", "titel": "typo"}`, http.StatusBadRequest, `json: unknown field "titel"`},
+		{"KnownFieldsAllowed", true, http.MethodPost, `{"title": "Album1"}`, http.StatusCreated, ""},
+		{"UnknownFieldRejectedPUT", true, http.MethodPut, `{"id": "cljcqg5o402e9s28rbp0", "titel": "typo"}`, http.StatusBadRequest, `json: unknown field "titel"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+			if tt.strict {
+				api.WithStrictBinding()
+			}
+
+			path := "/albums"
+			if tt.method == http.MethodPut {
+				path += "/cljcqg5o402e9s28rbp0"
+			}
+
+			r, err := http.NewRequest(tt.method, path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+
+			if tt.expectedError != "" {
+				var errResp babyapi.ErrResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+				require.Equal(t, tt.expectedError, errResp.ErrorText)
+			}
+		})
+	}
+}
+
+func TestJSONObjectBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		bulk           bool
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{"Object", false, `{"title": "Album1"}`, http.StatusCreated, ""},
+		{"Array", false, ` [{"title": "Album1"}]`, http.StatusBadRequest, "expected a JSON object but got an array"},
+		{"String", false, `"Album1"`, http.StatusBadRequest, "expected a JSON object but got a string"},
+		{"Number", false, `1`, http.StatusBadRequest, "expected a JSON object but got a number"},
+		{"ArrayWithBulkCreate", true, "\n[{\"title\": \"Album1\"}]", http.StatusMultiStatus, ""},
+		{"ObjectWithBulkCreate", true, `{"title": "Album1"}`, http.StatusCreated, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+			if tt.bulk {
+				api.EnableBulkCreate()
+			}
+
+			r, err := http.NewRequest(http.MethodPost, "/albums", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+
+			if tt.expectedError != "" {
+				var errResp babyapi.ErrResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+				require.Equal(t, tt.expectedError, errResp.ErrorText)
+			}
+		})
+	}
+}
+
+type TenantAlbum struct {
+	babyapi.DefaultResource
+	Tenant string `json:"tenant"`
+	Title  string `json:"title"`
+}
+
+func (a *TenantAlbum) StorageKey() string {
+	return a.Tenant + "/" + a.GetID()
+}
+
+func TestStorageKeyer(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *TenantAlbum { return &TenantAlbum{} }).
+		SetStorageKeyFunc(func(r *http.Request, id string) string {
+			return r.Header.Get("X-Tenant") + "/" + id
+		})
+
+	album := &TenantAlbum{DefaultResource: babyapi.NewDefaultResource(), Tenant: "tenant1", Title: "Album1"}
+
+	client, stop := babytest.NewTestClient[*TenantAlbum](t, api)
+	defer stop()
+
+	client.SetRequestEditor(func(r *http.Request) error {
+		r.Header.Set("X-Tenant", "tenant1")
+		return nil
+	})
+
+	t.Run("SetUsesStorageKey", func(t *testing.T) {
+		_, err := client.Put(context.Background(), album)
+		require.NoError(t, err)
+
+		result, err := api.Storage.Get(context.Background(), "tenant1/"+album.GetID())
+		require.NoError(t, err)
+		require.Equal(t, album, result)
+
+		_, err = api.Storage.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("GetUsesStorageKeyFunc", func(t *testing.T) {
+		result, err := client.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		require.Equal(t, album, result.Data)
+	})
+
+	t.Run("DeleteUsesStorageKeyFunc", func(t *testing.T) {
+		_, err := client.Delete(context.Background(), album.GetID())
+		require.NoError(t, err)
+
+		_, err = api.Storage.Get(context.Background(), "tenant1/"+album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("RequiresStorageKeyFunc", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *TenantAlbum { return &TenantAlbum{} })
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, "SetStorageKeyFunc: required because resource type *babyapi_test.TenantAlbum implements StorageKeyer")
+	})
+}
+
+func TestMaxInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithMaxInFlight(1).
+		AddCustomRoute(http.MethodGet, "/block", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums/block", http.NoBody))
+		done <- w.Result().StatusCode
+	}()
+	<-started
+
+	t.Run("RejectedWhenLimitReached", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums", http.NoBody))
+		require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+		require.Equal(t, "1", w.Result().Header.Get("Retry-After"))
+		require.Equal(t, `{"status":"Service unavailable.","error_code":"service_unavailable"}`, strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("ExemptPathsAllowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	close(release)
+	require.Equal(t, http.StatusOK, <-done)
+
+	t.Run("AllowedAfterRelease", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums", http.NoBody))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+}
+
+type UnserializableResource struct {
+	babyapi.DefaultResource
+	Data any `json:"data"`
+}
+
+func TestSerializationError(t *testing.T) {
+	resource := &UnserializableResource{DefaultResource: babyapi.NewDefaultResource(), Data: make(chan int)}
+
+	tests := []struct {
+		name         string
+		devMode      bool
+		expectedBody string
+	}{
+		{
+			"Default",
+			false,
+			`{"status":"Error serializing response.","error_code":"serialization_error"}`,
+		},
+		{
+			"DevMode",
+			true,
+			`{"status":"Error serializing response.","error_code":"serialization_error","error":"error serializing *babyapi_test.UnserializableResource: json: unsupported type: chan int"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Resources", "/resources", func() *UnserializableResource { return &UnserializableResource{} }).
+				AddCustomRoute(http.MethodGet, "/unserializable", babyapi.Handler(func(http.ResponseWriter, *http.Request) render.Renderer {
+					return resource
+				}))
+			if tt.devMode {
+				api.WithDevMode()
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "/resources/unserializable", http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*UnserializableResource](t, api, r)
+			require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+			require.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestWithBasePath(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithBasePath("/v2/records")
+
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	require.NoError(t, api.Storage.Set(context.Background(), album))
+
+	require.Equal(t, "/v2/records", api.Base())
+	require.Equal(t, "Albums", api.Name())
+	require.Equal(t, "AlbumsID", api.IDParamKey())
+
+	client, stop := babytest.NewTestClient[*Album](t, api)
+	defer stop()
+
+	result, err := client.Get(context.Background(), album.GetID())
+	require.NoError(t, err)
+	require.Equal(t, album, result.Data)
+
+	r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+	require.NoError(t, err)
+
+	w := babytest.TestRequest[*Album](t, api, r)
+	require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestEmptyID(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+
+	t.Run("GetRequestedResource", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/albums/", http.NoBody)
+
+		_, httpErr := api.GetRequestedResource(r)
+		require.NotNil(t, httpErr)
+		require.Equal(t, http.StatusBadRequest, httpErr.HTTPStatusCode)
+		require.ErrorIs(t, httpErr.Err, babyapi.ErrMissingID)
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			r, err := http.NewRequest(method, "/albums//", bytes.NewBufferString(`{"title": "Album1"}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+			require.Equal(t, `{"status":"Invalid request.","error_code":"invalid_request","error":"missing resource ID"}`, strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
+
+type OrgAlbum struct {
+	babyapi.DefaultResource
+	OrgID string `json:"org_id"`
+}
+
+func TestContextValue(t *testing.T) {
+	orgIDKey := babyapi.NewContextValueKey[string]("orgID")
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *OrgAlbum { return &OrgAlbum{} }).
+		AddMiddleware(babyapi.WithContextValue(orgIDKey, func(r *http.Request) (string, *babyapi.ErrResponse) {
+			orgID := r.Header.Get("X-Org-ID")
+			if orgID == "" {
+				return "", babyapi.ErrForbidden
+			}
+			return orgID, nil
+		})).
+		SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*OrgAlbum] {
+			orgID, _ := babyapi.GetContextValue(r.Context(), orgIDKey)
+			return func(a *OrgAlbum) bool {
+				return a.OrgID == orgID
+			}
+		})
+
+	album1 := &OrgAlbum{DefaultResource: babyapi.NewDefaultResource(), OrgID: "org1"}
+	album2 := &OrgAlbum{DefaultResource: babyapi.NewDefaultResource(), OrgID: "org2"}
+	require.NoError(t, api.Storage.Set(context.Background(), album1))
+	require.NoError(t, api.Storage.Set(context.Background(), album2))
+
+	t.Run("FilterUsesContextValue", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("X-Org-ID", "org1")
+
+		w := babytest.TestRequest[*OrgAlbum](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"items":[{"id":"`+album1.GetID()+`","org_id":"org1"}]}`, w.Body.String())
+	})
+
+	t.Run("ExtractorError", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*OrgAlbum](t, api, r)
+		require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	})
+
+	t.Run("UniqueKeys", func(t *testing.T) {
+		otherKey := babyapi.NewContextValueKey[string]("orgID")
+
+		ctx := babyapi.SetContextValue(context.Background(), orgIDKey, "org1")
+
+		value, ok := babyapi.GetContextValue(ctx, orgIDKey)
+		require.True(t, ok)
+		require.Equal(t, "org1", value)
+
+		_, ok = babyapi.GetContextValue(ctx, otherKey)
+		require.False(t, ok)
+	})
+}
+
+func TestErrorCodes(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		SetOnCreateOrUpdate(func(_ http.ResponseWriter, _ *http.Request, a *Album) *babyapi.ErrResponse {
+			if a.Title == "" {
+				return babyapi.ErrInvalidRequestCoded("missing_title", errors.New("title is required"))
+			}
+			return nil
+		})
+
+	r, err := http.NewRequest(http.MethodPost, "/albums", bytes.NewBufferString(`{}`))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/json")
+
+	w := babytest.TestRequest[*Album](t, api, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	require.Equal(t, `{"status":"Invalid request.","error_code":"missing_title","error":"title is required"}`, strings.TrimSpace(w.Body.String()))
+}
+
+type consistencyStorage struct {
+	babyapi.Storage[*Album]
+	consistency babyapi.Consistency
+}
+
+func (s *consistencyStorage) Get(ctx context.Context, id string) (*Album, error) {
+	s.consistency = babyapi.GetConsistencyFromContext(ctx)
+	return s.Storage.Get(ctx, id)
+}
+
+func TestConsistency(t *testing.T) {
+	storage := &consistencyStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		SetStorage(storage).
+		WithConsistencyHeader()
+
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	require.NoError(t, storage.Set(context.Background(), album))
+
+	tests := []struct {
+		name                string
+		header              string
+		expectedStatus      int
+		expectedConsistency babyapi.Consistency
+	}{
+		{"Default", "", http.StatusOK, babyapi.DefaultConsistency},
+		{"Strong", "strong", http.StatusOK, babyapi.ConsistencyStrong},
+		{"Eventual", "eventual", http.StatusOK, babyapi.ConsistencyEventual},
+		{"CaseInsensitive", "Strong", http.StatusOK, babyapi.ConsistencyStrong},
+		{"Invalid", "bad", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage.consistency = ""
+
+			r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+			require.NoError(t, err)
+			if tt.header != "" {
+				r.Header.Set(babyapi.ConsistencyHeader, tt.header)
+			}
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.Equal(t, tt.expectedConsistency, storage.consistency)
+		})
+	}
+
+	t.Run("IgnoredWithoutOption", func(t *testing.T) {
+		storage.consistency = ""
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage)
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set(babyapi.ConsistencyHeader, "bad")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, babyapi.DefaultConsistency, storage.consistency)
+	})
+}
+
+type DefaultsAlbum struct {
+	babyapi.DefaultResource
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Rating *int   `json:"rating"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (a *DefaultsAlbum) ApplyDefaults() {
+	a.Status = "new"
+	rating := 3
+	a.Rating = &rating
+	a.Labels = map[string]string{"source": "default"}
+}
+
+func ptr[V any](v V) *V {
+	return &v
+}
+
+func TestDefaultable(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus string
+		expectedRating *int
+	}{
+		{"PostOmittedFieldsUseDefaults", http.MethodPost, `{"title":"Album1"}`, "new", ptr(3)},
+		{"PostProvidedFieldsOverrideDefaults", http.MethodPost, `{"title":"Album1","status":"old","rating":5}`, "old", ptr(5)},
+		{"PostExplicitNullIsKept", http.MethodPost, `{"title":"Album1","rating":null}`, "new", nil},
+		{"PutDoesNotUseDefaults", http.MethodPut, `{"id":"cljcqg5o402e9s28rbp0","title":"Album1"}`, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *DefaultsAlbum { return &DefaultsAlbum{} })
+
+			path := "/albums"
+			if tt.method == http.MethodPut {
+				path += "/cljcqg5o402e9s28rbp0"
+			}
+
+			r, err := http.NewRequest(tt.method, path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*DefaultsAlbum](t, api, r)
+			require.Less(t, w.Result().StatusCode, 300)
+
+			var result DefaultsAlbum
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.Equal(t, tt.expectedStatus, result.Status)
+			require.Equal(t, tt.expectedRating, result.Rating)
+		})
+	}
+
+	t.Run("ProvidedMapIsNotMergedWithDefault", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *DefaultsAlbum { return &DefaultsAlbum{} })
+
+		r, err := http.NewRequest(http.MethodPost, "/albums", bytes.NewBufferString(`{"title":"Album1","labels":{"genre":"rock"}}`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*DefaultsAlbum](t, api, r)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+
+		var result DefaultsAlbum
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.Equal(t, map[string]string{"genre": "rock"}, result.Labels)
+		require.Equal(t, "new", result.Status)
+	})
+}
+
+func TestAllowEmptyBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		allow          bool
+		body           string
+		contentType    string
+		expectedStatus int
+		expectedTitle  string
+	}{
+		{"EmptyBodyUsesDefaults", true, "", "", http.StatusCreated, ""},
+		{"WhitespaceBodyUsesDefaults", true, " \n", "application/json", http.StatusCreated, ""},
+		{"LongWhitespaceBodyUsesDefaults", true, strings.Repeat(" ", 5000), "application/json", http.StatusCreated, ""},
+		{"BodyIsStillBound", true, `{"title":"Album1"}`, "application/json", http.StatusCreated, "Album1"},
+		{
+			"BodyAfterLongWhitespaceIsStillBound",
+			true,
+			strings.Repeat(" ", 5000) + `{"title":"Album1"}`,
+			"application/json",
+			http.StatusCreated,
+			"Album1",
+		},
+		{"EmptyBodyNotAllowed", false, "", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *DefaultsAlbum { return &DefaultsAlbum{} })
+			if tt.allow {
+				api.WithAllowEmptyBody()
+			}
+
+			r, err := http.NewRequest(http.MethodPost, "/albums", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := babytest.TestRequest[*DefaultsAlbum](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var result DefaultsAlbum
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.NotEmpty(t, result.GetID())
+			require.Equal(t, tt.expectedTitle, result.Title)
+			require.Equal(t, "new", result.Status)
+			require.Equal(t, ptr(3), result.Rating)
+
+			_, err = api.Storage.Get(context.Background(), result.GetID())
+			require.NoError(t, err)
+		})
+	}
+}
+
+type SoftDeleteAlbum struct {
+	babyapi.DefaultResource
+	Title   string     `json:"title"`
+	EndDate *time.Time `json:"end_date,omitempty"`
+}
+
+func (a *SoftDeleteAlbum) EndDated() bool {
+	return a.EndDate != nil && a.EndDate.Before(time.Now())
+}
+
+func (a *SoftDeleteAlbum) SetEndDate(t time.Time) {
+	a.EndDate = &t
+}
+
+func TestGoneForSoftDeleted(t *testing.T) {
+	tests := []struct {
+		name                string
+		gone                bool
+		expectedStatusAfter int
+		expectedErrorCode   string
+	}{
+		{"Disabled", false, http.StatusOK, ""},
+		{"Enabled", true, http.StatusGone, babyapi.ErrCodeGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *SoftDeleteAlbum { return &SoftDeleteAlbum{} })
+			if tt.gone {
+				api.WithGoneForSoftDeleted()
+			}
+
+			album := &SoftDeleteAlbum{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+			require.NoError(t, api.Storage.Set(context.Background(), album))
+
+			doRequest := func(method string) *httptest.ResponseRecorder {
+				r, err := http.NewRequest(method, "/albums/"+album.GetID(), http.NoBody)
+				require.NoError(t, err)
+				return babytest.TestRequest[*SoftDeleteAlbum](t, api, r)
+			}
+
+			require.Equal(t, http.StatusOK, doRequest(http.MethodGet).Result().StatusCode)
+			require.Equal(t, http.StatusNoContent, doRequest(http.MethodDelete).Result().StatusCode)
+
+			w := doRequest(http.MethodGet)
+			require.Equal(t, tt.expectedStatusAfter, w.Result().StatusCode)
+			if tt.expectedErrorCode != "" {
+				var errResp babyapi.ErrResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+				require.Equal(t, tt.expectedErrorCode, errResp.Code)
+			}
+
+			// Deleting again still permanently deletes the resource
+			require.Equal(t, http.StatusNoContent, doRequest(http.MethodDelete).Result().StatusCode)
+			require.Equal(t, http.StatusNotFound, doRequest(http.MethodGet).Result().StatusCode)
+		})
+	}
+}
+
+type ExampleAlbum struct {
+	babyapi.DefaultResource
+	Title string `json:"title"`
+}
+
+func (*ExampleAlbum) Examples() []*ExampleAlbum {
+	return []*ExampleAlbum{{DefaultResource: babyapi.NewDefaultResource(), Title: "Example1"}}
+}
+
+type DriftedAlbum struct {
+	babyapi.DefaultResource
+	Title string `json:"title"`
+}
+
+func (a *DriftedAlbum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"id": a.ID, "name": a.Title})
+}
+
+func TestExamples(t *testing.T) {
+	t.Run("ExamplerAndWithExamples", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *ExampleAlbum { return &ExampleAlbum{} }).
+			WithExamples(&ExampleAlbum{DefaultResource: babyapi.NewDefaultResource(), Title: "Example2"})
+
+		examples := api.Examples()
+		require.Len(t, examples, 2)
+		require.Equal(t, "Example1", examples[0].Title)
+		require.Equal(t, "Example2", examples[1].Title)
+
+		_, err := api.Router()
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidExample", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *DriftedAlbum { return &DriftedAlbum{} }).
+			WithExamples(&DriftedAlbum{DefaultResource: babyapi.NewDefaultResource(), Title: "Example"})
+
+		_, err := api.Router()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `invalid example 0: json: unknown field "name"`)
+	})
+}
+
+type deadlineStorage struct {
+	babyapi.Storage[*Album]
+	timeouts chan time.Duration
+}
+
+// record sends the context's timeout, or zero if it doesn't have a deadline. Only the first call is recorded
+func (s *deadlineStorage) record(ctx context.Context) {
+	var timeout time.Duration
+	deadline, ok := ctx.Deadline()
+	if ok {
+		timeout = time.Until(deadline)
+	}
+
+	select {
+	case s.timeouts <- timeout:
+	default:
+	}
+}
+
+func (s *deadlineStorage) Get(ctx context.Context, id string) (*Album, error) {
+	s.record(ctx)
+	return s.Storage.Get(ctx, id)
+}
+
+func (s *deadlineStorage) GetAll(ctx context.Context, query url.Values) ([]*Album, error) {
+	s.record(ctx)
+	return s.Storage.GetAll(ctx, query)
+}
+
+// blockingStorage waits for the context to be done before returning from Get
+type blockingStorage struct {
+	babyapi.Storage[*Album]
+}
+
+func (blockingStorage) Get(ctx context.Context, _ string) (*Album, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestMethodTimeout(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	tests := []struct {
+		name            string
+		timeouts        map[string]time.Duration
+		path            string
+		expectedTimeout time.Duration
+	}{
+		{"NoTimeout", map[string]time.Duration{}, "/albums", 0},
+		{"GetAll", map[string]time.Duration{babyapi.MethodGetAll: time.Minute, http.MethodGet: time.Second}, "/albums", time.Minute},
+		{"GetAllUsesGet", map[string]time.Duration{http.MethodGet: time.Second}, "/albums", time.Second},
+		{"Get", map[string]time.Duration{babyapi.MethodGetAll: time.Minute, http.MethodGet: time.Second}, "/albums/" + album.GetID(), time.Second},
+		{"OtherMethod", map[string]time.Duration{http.MethodPut: time.Second}, "/albums/" + album.GetID(), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &deadlineStorage{
+				Storage:  babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+				timeouts: make(chan time.Duration, 1),
+			}
+			require.NoError(t, storage.Set(context.Background(), album))
+
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				SetStorage(storage)
+			for method, timeout := range tt.timeouts {
+				api.WithMethodTimeout(method, timeout)
+			}
+
+			r, err := http.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+			timeout := <-storage.timeouts
+			if tt.expectedTimeout == 0 {
+				require.Zero(t, timeout)
+				return
+			}
+			require.InDelta(t, tt.expectedTimeout, timeout, float64(100*time.Millisecond))
+		})
+	}
+
+	t.Run("CancelsStorage", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(blockingStorage{}).
+			WithMethodTimeout(http.MethodGet, time.Millisecond)
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
+
+func TestCustomIDRouteWithPathParams(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+	api.AddCustomIDRoute(http.MethodGet, "/tracks/{trackID}", api.GetRequestedResourceAndDo(func(r *http.Request, album *Album) (render.Renderer, *babyapi.ErrResponse) {
+		return &Album{
+			DefaultResource: album.DefaultResource,
+			Title:           album.Title + " " + babyapi.GetPathParam(r, "trackID"),
+		}, nil
+	}))
+
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	require.NoError(t, api.Storage.Set(context.Background(), album))
+
+	t.Run("Successful", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID()+"/tracks/track1", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, `{"id":"`+album.GetID()+`","title":"Album1 track1"}`, strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums/DoesNotExist/tracks/track1", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestSetResponseStatus(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+	api.AddCustomIDRoute(http.MethodPost, "/process", api.GetRequestedResourceAndDo(func(r *http.Request, album *Album) (render.Renderer, *babyapi.ErrResponse) {
+		if album.Title == "Queued" {
+			babyapi.SetResponseStatus(r, http.StatusAccepted)
+		}
+		return album, nil
+	}))
+
+	queued := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Queued"}
+	require.NoError(t, api.Storage.Set(context.Background(), queued))
+	done := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Done"}
+	require.NoError(t, api.Storage.Set(context.Background(), done))
+
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{"Accepted", queued.GetID(), http.StatusAccepted},
+		{"DefaultStatus", done.GetID(), http.StatusOK},
+		{"ErrorIgnoresStatus", "DoesNotExist", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "/albums/"+tt.id+"/process", http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
 type SongResponse struct {
 	*Song
 	AlbumTitle string `json:"album_title"`
@@ -412,6 +1295,56 @@ func TestNestedAPI(t *testing.T) {
 	})
 }
 
+type ArtistSingle struct {
+	babyapi.DefaultResource
+	ArtistID string `json:"artist_id"`
+	Title    string `json:"title"`
+}
+
+func (s *ArtistSingle) GetParentID(name string) (string, bool) {
+	if name == "Artists" {
+		return s.ArtistID, true
+	}
+	return "", false
+}
+
+func TestNestedPutParentID(t *testing.T) {
+	artistAPI := babyapi.NewAPI("Artists", "/artists", func() *Artist { return &Artist{} })
+	singleAPI := babyapi.NewAPI("Singles", "/singles", func() *ArtistSingle { return &ArtistSingle{} })
+	artistAPI.AddNestedAPI(singleAPI)
+
+	artist := &Artist{DefaultResource: babyapi.NewDefaultResource(), Name: "Artist"}
+	require.NoError(t, artistAPI.Storage.Set(context.Background(), artist))
+
+	single := &ArtistSingle{DefaultResource: babyapi.NewDefaultResource()}
+	path := "/artists/" + artist.GetID() + "/singles/" + single.GetID()
+
+	tests := []struct {
+		name           string
+		artistID       string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"MatchingParentID", artist.GetID(), http.StatusOK, ""},
+		{"EmptyParentID", "", http.StatusBadRequest, `{"status":"Invalid request.","error_code":"invalid_request","error":"missing parent ID for Artists"}`},
+		{"MismatchedParentID", "cljcqg5o402e9s28rbp0", http.StatusBadRequest, `{"status":"Invalid request.","error_code":"invalid_request","error":"parent ID for Artists must match URL path"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"id":%q,"artist_id":%q,"title":"Single"}`, single.GetID(), tt.artistID)
+			r := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Artist](t, artistAPI, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedBody != "" {
+				require.Equal(t, tt.expectedBody, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
 func runCommand(cmd *cobra.Command, args []string) (string, error) {
 	var buf bytes.Buffer
 	cmd.SetArgs(args)
@@ -449,7 +1382,7 @@ func TestCLI(t *testing.T) {
 		{
 			"PostError",
 			[]string{"Albums", "post", "-d", `bad request`},
-			`{"error":"invalid character 'b' looking for beginning of value","status":"Invalid request."}`,
+			`{"error":"invalid character 'b' looking for beginning of value","error_code":"invalid_request","status":"Invalid request."}`,
 			false,
 		},
 		{
@@ -467,7 +1400,7 @@ func TestCLI(t *testing.T) {
 		{
 			"PutError",
 			[]string{"Albums", "put", "cljcqg5o402e9s28rbp0", "-d", `{"title":"NewAlbum"}`},
-			`{"error":"missing required id field","status":"Invalid request."}`,
+			`{"error":"missing required id field","error_code":"invalid_request","status":"Invalid request."}`,
 			false,
 		},
 		{
@@ -521,19 +1454,19 @@ func TestCLI(t *testing.T) {
 		{
 			"GetByIDNotFound",
 			[]string{"Albums", "get", "cljcqg5o402e9s28rbp0"},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
 			"DeleteNotFound",
 			[]string{"Albums", "delete", "cljcqg5o402e9s28rbp0"},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
 			"PatchNotFound",
 			[]string{"Albums", "patch", "cljcqg5o402e9s28rbp0", "-d", ""},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
@@ -714,6 +1647,109 @@ func TestHTML(t *testing.T) {
 	})
 }
 
+type Paragraph struct {
+	babyapi.DefaultResource
+	Content string
+}
+
+func (p *Paragraph) HTML(*http.Request) string {
+	tmpl := template.Must(template.New("p").Parse(`<p>{{ .Content }}</p>`))
+	return babyapi.MustRenderHTML(tmpl, p)
+}
+
+type albumsHTML struct {
+	babyapi.ResourceList[*Album]
+}
+
+func (a *albumsHTML) HTML(*http.Request) string {
+	return fmt.Sprintf("<div>%d albums</div>", len(a.Items))
+}
+
+func TestResourceListHTML(t *testing.T) {
+	t.Run("DefaultTemplate", func(t *testing.T) {
+		api := babyapi.NewAPI("Paragraphs", "/paragraphs", func() *Paragraph { return &Paragraph{} })
+
+		err := api.Storage.Set(context.Background(), &Paragraph{DefaultResource: babyapi.NewDefaultResource(), Content: "Paragraph1"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest(http.MethodGet, "/paragraphs", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Paragraph](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "text/html; charset=utf-8", w.Result().Header.Get("Content-Type"))
+		require.Equal(t, `<ul>
+<li><p>Paragraph1</p></li>
+</ul>`, w.Body.String())
+	})
+
+	t.Run("EmptyList", func(t *testing.T) {
+		api := babyapi.NewAPI("Paragraphs", "/paragraphs", func() *Paragraph { return &Paragraph{} })
+
+		r, err := http.NewRequest(http.MethodGet, "/paragraphs", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Paragraph](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "text/html; charset=utf-8", w.Result().Header.Get("Content-Type"))
+		require.Equal(t, "<ul>\n</ul>", w.Body.String())
+	})
+
+	t.Run("WithCollectionHTMLTemplate", func(t *testing.T) {
+		api := babyapi.NewAPI("Paragraphs", "/paragraphs", func() *Paragraph { return &Paragraph{} }).
+			WithCollectionHTMLTemplate(template.Must(template.New("table").Parse(
+				`<table>{{ range .Items }}<tr><td>{{ . }}</td></tr>{{ end }}</table><span>{{ .Count }} items, filter={{ .Query.Get "filter" }}</span>`,
+			)))
+
+		err := api.Storage.Set(context.Background(), &Paragraph{DefaultResource: babyapi.NewDefaultResource(), Content: "Paragraph1"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest(http.MethodGet, "/paragraphs?filter=abc", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Paragraph](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, `<table><tr><td><p>Paragraph1</p></td></tr></table><span>1 items, filter=abc</span>`, w.Body.String())
+	})
+
+	t.Run("ResponseWrapperHTML", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetGetAllResponseWrapper(func(albums []*Album) render.Renderer {
+				return &albumsHTML{babyapi.ResourceList[*Album]{albums}}
+			})
+
+		err := api.Storage.Set(context.Background(), &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "<div>1 albums</div>", w.Body.String())
+	})
+
+	t.Run("FallbackToJSONForNonHTMLer", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+
+		err := api.Storage.Set(context.Background(), &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"})
+		require.NoError(t, err)
+
+		r, err := http.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+		require.Regexp(t, `{"items":\[{"id":"[0-9a-v]{20}","title":"Album1"}\]}`, w.Body.String())
+	})
+}
+
 func TestServerSentEvents(t *testing.T) {
 	api := babyapi.NewAPI("Items", "/items", func() *ListItem { return &ListItem{} })
 
@@ -775,6 +1811,47 @@ func TestMustRenderHTML(t *testing.T) {
 	})
 }
 
+func TestRenderHTML(t *testing.T) {
+	tmpl := template.Must(template.New("test").Parse("<p>{{ . }}</p>"))
+
+	t.Run("Successful", func(t *testing.T) {
+		result, err := babyapi.RenderHTML(context.Background(), tmpl, "hello")
+		require.NoError(t, err)
+		require.Equal(t, "<p>hello</p>", result)
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := babyapi.RenderHTML(ctx, tmpl, "hello")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("TemplateError", func(t *testing.T) {
+		_, err := babyapi.RenderHTML(context.Background(), template.Must(template.New("test").Parse("{{ .UndefinedVariable }}")), "string is bad input")
+		require.Error(t, err)
+	})
+
+	t.Run("ResourceListClientClosedRequest", func(t *testing.T) {
+		api := babyapi.NewAPI("Paragraphs", "/paragraphs", func() *Paragraph { return &Paragraph{} })
+
+		err := api.Storage.Set(context.Background(), &Paragraph{DefaultResource: babyapi.NewDefaultResource(), Content: "Paragraph1"})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/paragraphs", http.NoBody)
+		require.NoError(t, err)
+		r.Header.Set("Accept", "text/html")
+
+		w := babytest.TestRequest[*Paragraph](t, api, r)
+		require.Equal(t, babyapi.StatusClientClosedRequest, w.Result().StatusCode)
+		require.Empty(t, w.Body.String())
+	})
+}
+
 func TestAPIModifiers(t *testing.T) {
 	middleware := 0
 	idMiddlewareWithRequestResource := 0
@@ -1134,7 +2211,7 @@ func TestRootAPICLI(t *testing.T) {
 		{
 			"PostError",
 			[]string{"MusicVideos", "post", "--data", `bad request`},
-			`{"error":"invalid character 'b' looking for beginning of value","status":"Invalid request."}`,
+			`{"error":"invalid character 'b' looking for beginning of value","error_code":"invalid_request","status":"Invalid request."}`,
 			false,
 		},
 		{
@@ -1152,7 +2229,7 @@ func TestRootAPICLI(t *testing.T) {
 		{
 			"PutError",
 			[]string{"MusicVideos", "put", "cljcqg5o402e9s28rbp0", "--data", `{"title":"NewMusicVideo"}`},
-			`{"error":"missing required id field","status":"Invalid request."}`,
+			`{"error":"missing required id field","error_code":"invalid_request","status":"Invalid request."}`,
 			false,
 		},
 		{
@@ -1200,19 +2277,19 @@ func TestRootAPICLI(t *testing.T) {
 		{
 			"GetByIDNotFound",
 			[]string{"MusicVideos", "get", "cljcqg5o402e9s28rbp0"},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
 			"DeleteNotFound",
 			[]string{"MusicVideos", "delete", "cljcqg5o402e9s28rbp0"},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
 			"PatchNotFound",
 			[]string{"MusicVideos", "patch", "cljcqg5o402e9s28rbp0", "--data", ""},
-			`{"status":"Resource not found."}`,
+			`{"error_code":"not_found","status":"Resource not found."}`,
 			false,
 		},
 		{
@@ -0,0 +1,115 @@
+package babyapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// BulkResult is the outcome of creating a single resource in a bulk create request. Index is the position
+// of the item in the request array so clients can correlate results
+type BulkResult[T Resource] struct {
+	Index    int          `json:"index"`
+	Status   int          `json:"status"`
+	Resource T            `json:"resource,omitempty"`
+	Error    *ErrResponse `json:"error,omitempty"`
+}
+
+// BulkResults is the response for a bulk create request. It is rendered as an array with a 207 Multi-Status
+// response code since each item has its own status
+type BulkResults[T Resource] []BulkResult[T]
+
+// Render implements render.Renderer
+func (BulkResults[T]) Render(_ http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusMultiStatus)
+	return nil
+}
+
+// EnableBulkCreate adds a POST /base/bulk route that accepts an array of resources and creates each of them
+// the same way as the default POST handler. POST requests to /base with an array are also sent to this handler.
+// The response is a BulkResults with one BulkResult per item
+func (a *API[T]) EnableBulkCreate() *API[T] {
+	a.panicIfReadOnly()
+
+	a.bulkCreate = true
+	return a
+}
+
+// bulkArrayMiddleware sends POST requests with a JSON array body to the bulk create handler so clients can use the
+// same endpoint for creating one or many resources
+func (a *API[T]) bulkArrayMiddleware(next http.Handler) http.Handler {
+	bulkCreate := a.defaultBulkCreate()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if render.GetRequestContentType(r) != render.ContentTypeJSON {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// A body that can't be read goes to the normal create handler to report the error
+		if first, _ := peekJSON(r); first == '[' {
+			bulkCreate.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *API[T]) defaultBulkCreate() http.HandlerFunc {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		logger := GetLoggerFromContext(r.Context())
+
+		var items []json.RawMessage
+		err := json.NewDecoder(r.Body).Decode(&items)
+		if err != nil {
+			return ErrInvalidRequest(fmt.Errorf("error decoding bulk request: %w", err))
+		}
+
+		logger.Info("creating resources", "count", len(items))
+
+		results := BulkResults[T]{}
+		for i, item := range items {
+			result := BulkResult[T]{Index: i}
+
+			resource, httpErr := a.storeBulkItem(w, r, http.MethodPost, item)
+			if httpErr != nil {
+				logger.Error("error creating resource", "index", i, "error", httpErr)
+				result.Status = httpErr.HTTPStatusCode
+				result.Error = httpErr
+			} else {
+				result.Status = a.responseCodes[http.MethodPost]
+				result.Resource = resource
+			}
+
+			results = append(results, result)
+		}
+
+		return results
+	})
+}
+
+// storeBulkItem binds a single item from a bulk request and stores it. A copy of the request with the item as its
+// body and the provided method is used so binding and the create/update hooks behave the same as a regular request
+func (a *API[T]) storeBulkItem(w http.ResponseWriter, r *http.Request, method string, item []byte) (T, *ErrResponse) {
+	itemRequest := r.Clone(r.Context())
+	itemRequest.Method = method
+	itemRequest.Body = io.NopCloser(bytes.NewReader(item))
+	itemRequest.ContentLength = int64(len(item))
+	itemRequest.Header.Set("Content-Type", "application/json")
+
+	resource, httpErr := a.GetFromRequest(itemRequest)
+	if httpErr != nil {
+		return *new(T), httpErr
+	}
+
+	httpErr = a.createResource(w, itemRequest, resource)
+	if httpErr != nil {
+		return *new(T), httpErr
+	}
+
+	return resource, nil
+}
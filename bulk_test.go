@@ -0,0 +1,70 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCreate(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		EnableBulkCreate()
+
+	t.Run("PartialSuccess", func(t *testing.T) {
+		body := `[{"title": "Album1"}, {"id": "cljcqg5o402e9s28rbp0", "title": "Album2"}, {"title": "Album3"}]`
+		r, err := http.NewRequest(http.MethodPost, "/albums/bulk", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+
+		var results []babyapi.BulkResult[*Album]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		require.Len(t, results, 3)
+
+		require.Equal(t, 0, results[0].Index)
+		require.Equal(t, http.StatusCreated, results[0].Status)
+		require.Equal(t, "Album1", results[0].Resource.Title)
+		require.Nil(t, results[0].Error)
+
+		require.Equal(t, 1, results[1].Index)
+		require.Equal(t, http.StatusBadRequest, results[1].Status)
+		require.Nil(t, results[1].Resource)
+		require.Equal(t, "unable to manually set ID", results[1].Error.ErrorText)
+
+		require.Equal(t, 2, results[2].Index)
+		require.Equal(t, http.StatusCreated, results[2].Status)
+		require.Equal(t, "Album3", results[2].Resource.Title)
+
+		stored, err := api.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodPost, "/albums/bulk", bytes.NewBufferString(`{"title": "Album1"}`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+
+		r, err := http.NewRequest(http.MethodPost, "/albums/bulk", bytes.NewBufferString(`[]`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
@@ -0,0 +1,145 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CascadeDeleteResult is the outcome of deleting a single child resource when its parent is deleted
+type CascadeDeleteResult struct {
+	API    string       `json:"api"`
+	ID     string       `json:"id,omitempty"`
+	Status int          `json:"status"`
+	Error  *ErrResponse `json:"error,omitempty"`
+}
+
+// CascadeDeleteResults is the response for a DELETE request when some child resources could not be deleted. It
+// is rendered with a 207 Multi-Status response code and includes the result for every child resource
+type CascadeDeleteResults []CascadeDeleteResult
+
+// Render implements render.Renderer
+func (CascadeDeleteResults) Render(_ http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusMultiStatus)
+	return nil
+}
+
+func (results CascadeDeleteResults) failed() bool {
+	for _, result := range results {
+		if result.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCascadeDelete is used on a nested API to delete its resources when the parent resource is deleted. The
+// function returns the parent ID of a resource so the children of a deleted parent can be found. This also applies
+// to the children of deleted children. Each child is deleted like a DELETE request to the nested API, so its before
+// and after delete functions and change events are used. Children are not deleted when the parent implements
+// EndDateable and is only soft-deleted. Failures do not stop the other deletes. Instead, the parent's DELETE request
+// responds with CascadeDeleteResults when any child could not be deleted
+func (a *API[T]) WithCascadeDelete(getParentID func(T) string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.cascadeParentID = getParentID
+	return a
+}
+
+// cascadeDeleteChildren deletes the resources from all child APIs that belong to the parent ID
+func (a *API[T]) cascadeDeleteChildren(w http.ResponseWriter, r *http.Request, id string) CascadeDeleteResults {
+	results := CascadeDeleteResults{}
+	for _, child := range a.subAPIs {
+		results = append(results, child.cascadeDelete(w, r, id)...)
+	}
+	return results
+}
+
+// cascadeDelete deletes this API's resources that belong to the parent ID, if cascade delete is enabled
+func (a *API[T]) cascadeDelete(w http.ResponseWriter, r *http.Request, parentID string) CascadeDeleteResults {
+	if a.cascadeParentID == nil {
+		return nil
+	}
+
+	logger := GetLoggerFromContext(r.Context())
+
+	resources, err := a.getReadStorage().GetAll(r.Context(), url.Values{})
+	if err != nil {
+		logger.Error("error getting resources for cascade delete", "api", a.name, "error", err)
+		return CascadeDeleteResults{{API: a.name, Status: http.StatusInternalServerError, Error: InternalServerError(err)}}
+	}
+
+	results := CascadeDeleteResults{}
+	for _, resource := range resources {
+		if a.cascadeParentID(resource) != parentID {
+			continue
+		}
+
+		result := CascadeDeleteResult{API: a.name, ID: resource.GetID(), Status: http.StatusNoContent}
+
+		httpErr := a.cascadeDeleteResource(w, a.cascadeDeleteRequest(r, resource), resource)
+		if httpErr != nil {
+			logger.Error("error cascade deleting resource", "api", a.name, "id", resource.GetID(), "error", httpErr)
+
+			result.Error = httpErr
+			result.Status = httpErr.HTTPStatusCode
+			results = append(results, result)
+			continue
+		}
+
+		logger.Info("cascade deleted resource", "api", a.name, "id", resource.GetID())
+		results = append(results, result)
+
+		if !isSoftDelete(resource) {
+			results = append(results, a.cascadeDeleteChildren(w, r, resource.GetID())...)
+		}
+	}
+
+	return results
+}
+
+// cascadeDeleteRequest creates a request for deleting the child resource so the before and after delete functions
+// and change events can use its ID and previous state like they do for a DELETE request
+func (a *API[T]) cascadeDeleteRequest(r *http.Request, resource T) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	if parentRouteCtx := chi.RouteContext(r.Context()); parentRouteCtx != nil {
+		routeCtx.URLParams.Keys = slices.Clone(parentRouteCtx.URLParams.Keys)
+		routeCtx.URLParams.Values = slices.Clone(parentRouteCtx.URLParams.Values)
+	}
+	routeCtx.URLParams.Add(a.IDParamKey(), resource.GetID())
+
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = a.newContextWithResource(ctx, resource)
+
+	return r.WithContext(ctx)
+}
+
+// cascadeDeleteResource deletes a child resource using the same steps as the default DELETE handler
+func (a *API[T]) cascadeDeleteResource(w http.ResponseWriter, r *http.Request, resource T) *ErrResponse {
+	httpErr := a.beforeDelete(w, r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	err := a.getWriteStorage().Delete(r.Context(), StorageKey(resource))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrNotFoundResponse
+		}
+		return InternalServerError(err)
+	}
+
+	httpErr = a.afterDelete(w, r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	a.emitChangeEvent(r, resource.GetID(), *new(T), true)
+
+	return nil
+}
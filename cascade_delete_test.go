@@ -0,0 +1,183 @@
+package babyapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type Library struct {
+	babyapi.DefaultResource
+}
+
+type Book struct {
+	babyapi.DefaultResource
+	LibraryID string `json:"library_id"`
+}
+
+type Page struct {
+	babyapi.DefaultResource
+	BookID string `json:"book_id"`
+}
+
+// failingDeleteStorage returns an error when deleting a specific ID
+type failingDeleteStorage[T babyapi.Resource] struct {
+	babyapi.Storage[T]
+	failID string
+}
+
+func (s failingDeleteStorage[T]) Delete(ctx context.Context, id string) error {
+	if id == s.failID {
+		return errors.New("delete failed")
+	}
+	return s.Storage.Delete(ctx, id)
+}
+
+func TestCascadeDelete(t *testing.T) {
+	library := &Library{DefaultResource: babyapi.NewDefaultResource()}
+	book1 := &Book{DefaultResource: babyapi.NewDefaultResource(), LibraryID: library.GetID()}
+	book2 := &Book{DefaultResource: babyapi.NewDefaultResource(), LibraryID: library.GetID()}
+	otherBook := &Book{DefaultResource: babyapi.NewDefaultResource(), LibraryID: "other"}
+	page := &Page{DefaultResource: babyapi.NewDefaultResource(), BookID: book1.GetID()}
+
+	setup := func(t *testing.T, failID string) (*babyapi.API[*Library], *babyapi.API[*Book], *babyapi.API[*Page]) {
+		libraryAPI := babyapi.NewAPI("Libraries", "/libraries", func() *Library { return &Library{} })
+		bookAPI := babyapi.NewAPI("Books", "/books", func() *Book { return &Book{} }).
+			SetStorage(failingDeleteStorage[*Book]{babyapi.NewKVStorage[*Book](kv.NewDefaultDB(), "Books"), failID}).
+			WithCascadeDelete(func(b *Book) string { return b.LibraryID })
+		pageAPI := babyapi.NewAPI("Pages", "/pages", func() *Page { return &Page{} }).
+			WithCascadeDelete(func(p *Page) string { return p.BookID })
+
+		libraryAPI.AddNestedAPI(bookAPI)
+		bookAPI.AddNestedAPI(pageAPI)
+
+		require.NoError(t, libraryAPI.Storage.Set(context.Background(), library))
+		for _, book := range []*Book{book1, book2, otherBook} {
+			require.NoError(t, bookAPI.Storage.Set(context.Background(), book))
+		}
+		require.NoError(t, pageAPI.Storage.Set(context.Background(), page))
+
+		return libraryAPI, bookAPI, pageAPI
+	}
+
+	t.Run("Successful", func(t *testing.T) {
+		libraryAPI, bookAPI, pageAPI := setup(t, "")
+
+		r, err := http.NewRequest(http.MethodDelete, "/libraries/"+library.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Library](t, libraryAPI, r)
+		require.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+
+		books, err := bookAPI.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, []*Book{otherBook}, books)
+
+		_, err = pageAPI.Storage.Get(context.Background(), page.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		libraryAPI, bookAPI, pageAPI := setup(t, book2.GetID())
+
+		r, err := http.NewRequest(http.MethodDelete, "/libraries/"+library.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Library](t, libraryAPI, r)
+		require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+
+		var results babyapi.CascadeDeleteResults
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		require.ElementsMatch(t, babyapi.CascadeDeleteResults{
+			{API: "Books", ID: book1.GetID(), Status: http.StatusNoContent},
+			{API: "Pages", ID: page.GetID(), Status: http.StatusNoContent},
+			{API: "Books", ID: book2.GetID(), Status: http.StatusInternalServerError, Error: &babyapi.ErrResponse{
+				StatusText: "Server Error.",
+				Code:       babyapi.ErrCodeInternal,
+				ErrorText:  "delete failed",
+			}},
+		}, results)
+
+		_, err = libraryAPI.Storage.Get(context.Background(), library.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+
+		books, err := bookAPI.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*Book{book2, otherBook}, books)
+
+		_, err = pageAPI.Storage.Get(context.Background(), page.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("ChildHooksAndEvents", func(t *testing.T) {
+		libraryAPI, bookAPI, _ := setup(t, "")
+
+		beforeDeleteIDs := []string{}
+		events := []babyapi.ChangeEvent[*Book]{}
+		bookAPI.
+			SetBeforeDelete(func(_ http.ResponseWriter, r *http.Request) *babyapi.ErrResponse {
+				beforeDeleteIDs = append(beforeDeleteIDs, bookAPI.GetIDParam(r))
+				if bookAPI.GetIDParam(r) == book2.GetID() {
+					return babyapi.ErrForbidden
+				}
+				return nil
+			}).
+			AddChangeEventListener(func(_ *http.Request, event babyapi.ChangeEvent[*Book]) {
+				events = append(events, event)
+			}).
+			WithEventIncludePrevious()
+
+		r, err := http.NewRequest(http.MethodDelete, "/libraries/"+library.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Library](t, libraryAPI, r)
+		require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+		require.ElementsMatch(t, []string{book1.GetID(), book2.GetID()}, beforeDeleteIDs)
+
+		require.Len(t, events, 1)
+		require.Equal(t, babyapi.ChangeEventDeleted, events[0].Type)
+		require.Equal(t, book1.GetID(), events[0].ID)
+		require.Equal(t, book1, events[0].Previous)
+
+		books, err := bookAPI.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*Book{book2, otherBook}, books)
+	})
+
+	t.Run("SoftDeletedParentKeepsChildren", func(t *testing.T) {
+		album := &SoftDeleteAlbum{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+		song := &Book{DefaultResource: babyapi.NewDefaultResource(), LibraryID: album.GetID()}
+
+		albumAPI := babyapi.NewAPI("Albums", "/albums", func() *SoftDeleteAlbum { return &SoftDeleteAlbum{} })
+		songAPI := babyapi.NewAPI("Songs", "/songs", func() *Book { return &Book{} }).
+			WithCascadeDelete(func(b *Book) string { return b.LibraryID })
+		albumAPI.AddNestedAPI(songAPI)
+
+		require.NoError(t, albumAPI.Storage.Set(context.Background(), album))
+		require.NoError(t, songAPI.Storage.Set(context.Background(), song))
+
+		deleteAlbum := func() {
+			r, err := http.NewRequest(http.MethodDelete, "/albums/"+album.GetID(), http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*SoftDeleteAlbum](t, albumAPI, r)
+			require.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+		}
+
+		deleteAlbum()
+		_, err := songAPI.Storage.Get(context.Background(), song.GetID())
+		require.NoError(t, err)
+
+		// Deleting again permanently deletes the album and its children
+		deleteAlbum()
+		_, err = songAPI.Storage.Get(context.Background(), song.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+}
@@ -0,0 +1,126 @@
+package babyapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// ChangeEventType is the kind of change in a ChangeEvent
+type ChangeEventType string
+
+const (
+	ChangeEventCreated ChangeEventType = "created"
+	ChangeEventUpdated ChangeEventType = "updated"
+	ChangeEventDeleted ChangeEventType = "deleted"
+)
+
+// ChangeEvent is emitted after a resource is created, updated, or deleted by the default handlers. Previous and
+// Changes are only set for updates and deletes when WithEventIncludePrevious is used
+type ChangeEvent[T Resource] struct {
+	Type     ChangeEventType `json:"type"`
+	ID       string          `json:"id"`
+	Resource T               `json:"resource,omitempty"`
+	Previous T               `json:"previous,omitempty"`
+	Changes  ChangedFields   `json:"changes,omitempty"`
+}
+
+// AddChangeEventListener adds a function that is called with each ChangeEvent, like for sending webhooks. Listeners
+// are called synchronously after the change is stored, so slow work should be done in a separate goroutine
+func (a *API[T]) AddChangeEventListener(listener func(*http.Request, ChangeEvent[T])) *API[T] {
+	a.panicIfReadOnly()
+
+	a.changeEventListeners = append(a.changeEventListeners, listener)
+	return a
+}
+
+// AddChangeEventStream adds a GET route at the pattern that streams ChangeEvents as server-sent events. The event name
+// is the ChangeEventType and the data is the JSON event. Write-only fields are not included. Since events are sent
+// from write requests, this stream never blocks on subscribers: EventBackpressureBlock is replaced by
+// EventBackpressureDropOldest with a buffer of 16 events unless WithEventBackpressure sets a size
+func (a *API[T]) AddChangeEventStream(pattern string) *API[T] {
+	a.panicIfReadOnly()
+
+	events := &broadcastChannel[*ServerSentEvent]{backpressure: &a.eventBackpressure, nonBlocking: true}
+	a.AddCustomRoute(http.MethodGet, pattern, a.HandleServerSentEvents(events))
+
+	return a.AddChangeEventListener(func(r *http.Request, event ChangeEvent[T]) {
+		data, err := encodeChangeEvent(event)
+		if err != nil {
+			GetLoggerFromContext(r.Context()).Error("error encoding change event", "error", err)
+			return
+		}
+
+		events.SendToAll(&ServerSentEvent{Event: string(event.Type), Data: string(data)})
+	})
+}
+
+// WithEventIncludePrevious includes the previous state of the resource in ChangeEvents for updates and deletes, along
+// with the changed fields from Diff
+func (a *API[T]) WithEventIncludePrevious() *API[T] {
+	a.panicIfReadOnly()
+
+	a.eventIncludePrevious = true
+	return a
+}
+
+// emitChangeEvent sends a ChangeEvent to the listeners. The previous resource is read from the request context, where
+// it is stored by resourceExistsMiddleware before the request modifies it
+func (a *API[T]) emitChangeEvent(r *http.Request, id string, resource T, deleted bool) {
+	if len(a.changeEventListeners) == 0 {
+		return
+	}
+
+	previous, err := a.GetResourceFromContext(r.Context())
+	exists := err == nil
+
+	event := ChangeEvent[T]{Type: ChangeEventCreated, ID: id, Resource: resource}
+	switch {
+	case deleted:
+		event.Type = ChangeEventDeleted
+	case exists:
+		event.Type = ChangeEventUpdated
+	}
+
+	if a.eventIncludePrevious && exists {
+		event.Previous = previous
+
+		if !deleted {
+			event.Changes, err = Diff(previous, resource)
+			if err != nil {
+				GetLoggerFromContext(r.Context()).Error("error getting changed fields for event", "error", err)
+			}
+		}
+	}
+
+	for _, listener := range a.changeEventListeners {
+		listener(r, event)
+	}
+}
+
+// encodeChangeEvent encodes the event as JSON without write-only fields
+func encodeChangeEvent[T Resource](event ChangeEvent[T]) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(event)
+	if !hasFieldAccessTags(t) {
+		return data, nil
+	}
+
+	generic, err := decodeJSONWithNumbers(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding event: %w", err)
+	}
+	removeFields(t, reflect.ValueOf(event), generic, FieldAccessWriteOnly)
+
+	// Changes only has the JSON fields, so the resource's type is used to find write-only fields
+	changes, _ := generic.(map[string]any)["changes"].(map[string]any)
+	removeFields(reflect.TypeOf(event.Resource), reflect.Value{}, changes, FieldAccessWriteOnly)
+
+	return json.Marshal(generic)
+}
@@ -0,0 +1,134 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeEvents(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	tests := []struct {
+		name            string
+		includePrevious bool
+		method          string
+		path            string
+		body            string
+		expectedType    babyapi.ChangeEventType
+		expectedTitle   string
+		expectPrevious  bool
+		expectedChanges babyapi.ChangedFields
+	}{
+		{"Create", false, http.MethodPost, "/albums", `{"title":"Album2"}`, babyapi.ChangeEventCreated, "Album2", false, nil},
+		{"CreateWithPUT", true, http.MethodPut, "/albums/cqsbrb9jkrnn3bhbaf5g", `{"id":"cqsbrb9jkrnn3bhbaf5g","title":"Album2"}`, babyapi.ChangeEventCreated, "Album2", false, nil},
+		{"Update", false, http.MethodPut, "/albums/" + album.GetID(), `{"id":"` + album.GetID() + `","title":"New"}`, babyapi.ChangeEventUpdated, "New", false, nil},
+		{"UpdateWithPrevious", true, http.MethodPut, "/albums/" + album.GetID(), `{"id":"` + album.GetID() + `","title":"New"}`, babyapi.ChangeEventUpdated, "New", true, babyapi.ChangedFields{"title": "New"}},
+		{"PatchWithPrevious", true, http.MethodPatch, "/albums/" + album.GetID(), `{"title":"New"}`, babyapi.ChangeEventUpdated, "New", true, babyapi.ChangedFields{"title": "New"}},
+		{"Delete", false, http.MethodDelete, "/albums/" + album.GetID(), "", babyapi.ChangeEventDeleted, "", false, nil},
+		{"DeleteWithPrevious", true, http.MethodDelete, "/albums/" + album.GetID(), "", babyapi.ChangeEventDeleted, "", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := []babyapi.ChangeEvent[*Album]{}
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				AddChangeEventListener(func(_ *http.Request, event babyapi.ChangeEvent[*Album]) {
+					events = append(events, event)
+				})
+			if tt.includePrevious {
+				api.WithEventIncludePrevious()
+			}
+			require.NoError(t, api.Storage.Set(context.Background(), &Album{DefaultResource: album.DefaultResource, Title: album.Title}))
+
+			r, err := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Less(t, w.Result().StatusCode, 300, w.Body.String())
+
+			require.Len(t, events, 1)
+			event := events[0]
+			require.Equal(t, tt.expectedType, event.Type)
+			require.NotEmpty(t, event.ID)
+			require.Equal(t, tt.expectedChanges, event.Changes)
+
+			if tt.expectedTitle == "" {
+				require.Nil(t, event.Resource)
+			} else {
+				require.Equal(t, tt.expectedTitle, event.Resource.Title)
+			}
+
+			if tt.expectPrevious {
+				require.Equal(t, "Album1", event.Previous.Title)
+			} else {
+				require.Nil(t, event.Previous)
+			}
+		})
+	}
+}
+
+// subscribedRecorder closes subscribed when the server-sent events handler sets its headers, which happens after it
+// starts listening for events
+type subscribedRecorder struct {
+	*httptest.ResponseRecorder
+	subscribed chan struct{}
+	once       sync.Once
+}
+
+func (w *subscribedRecorder) Header() http.Header {
+	w.once.Do(func() { close(w.subscribed) })
+	return w.ResponseRecorder.Header()
+}
+
+func TestChangeEventStreamSubscriberDisconnect(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		AddChangeEventStream("/events")
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	subscriber := &subscribedRecorder{ResponseRecorder: httptest.NewRecorder(), subscribed: make(chan struct{})}
+	subscriberDone := make(chan struct{})
+	go func() {
+		router.ServeHTTP(subscriber, httptest.NewRequest(http.MethodGet, "/albums/events", http.NoBody).WithContext(ctx))
+		close(subscriberDone)
+	}()
+	<-subscriber.subscribed
+
+	writesDone := make(chan struct{})
+	go func() {
+		defer close(writesDone)
+		for i := 0; i < 50; i++ {
+			if i == 1 {
+				// Disconnect while events are being sent
+				cancel()
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"Album"}`))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Result().StatusCode != http.StatusCreated {
+				t.Errorf("unexpected status: %d", w.Result().StatusCode)
+			}
+		}
+	}()
+
+	select {
+	case <-writesDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("write requests blocked after subscriber disconnected")
+	}
+	<-subscriberDone
+}
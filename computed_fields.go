@@ -0,0 +1,97 @@
+package babyapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// WithComputedFields sets a function that creates fields which are not stored with the resource, but are added to
+// its JSON responses. The fields are merged into the output of the response wrapper for each resource, including the
+// items in GetAll responses. Computed fields replace stored fields with the same name. The names of the fields that
+// are created can be provided so they are included in the OpenAPI document. Since computed fields can use any field
+// of the resource, ProjectingStorage is not used to read only the fields selected by WithFieldSelection
+func (a *API[T]) WithComputedFields(computedFields func(context.Context, T) map[string]any, names ...string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.computedFields = computedFields
+	a.computedFieldNames = names
+	return a
+}
+
+// wrapResponse creates the response for a resource using the response wrapper and adds computed fields
+func (a *API[T]) wrapResponse(r *http.Request, resource T) render.Renderer {
+	resp := a.responseWrapper(resource)
+
+	fields := map[string]any{}
+	if a.computedFields != nil {
+		fields = a.computedFields(r.Context(), resource)
+	}
+
+	if a.serverTime {
+		fields = a.addServerTimeFields(r, resource, fields)
+	}
+
+	if a.hateoas {
+		fields = a.addLinks(r, resource, fields)
+	}
+
+	if len(fields) == 0 {
+		return resp
+	}
+
+	computed := computedFieldsResponse{resp, fields}
+
+	switch resp.(type) {
+	case HTMLRenderer:
+		return computedFieldsHTMLRendererResponse{computed}
+	case HTMLer:
+		return computedFieldsHTMLResponse{computed}
+	default:
+		return computed
+	}
+}
+
+// computedFieldsResponse adds computed fields to the JSON of a Renderer
+type computedFieldsResponse struct {
+	render.Renderer
+	fields map[string]any
+}
+
+func (c computedFieldsResponse) MarshalJSON() ([]byte, error) {
+	data, err := toJSONMap(c.Renderer)
+	if err != nil {
+		return nil, fmt.Errorf("error adding computed fields: %w", err)
+	}
+
+	for key, value := range c.fields {
+		data[key] = value
+	}
+
+	return json.Marshal(data)
+}
+
+func (c computedFieldsResponse) unwrapResponse() any {
+	return c.Renderer
+}
+
+// computedFieldsHTMLResponse keeps the HTML response when computed fields are added to an HTMLer
+type computedFieldsHTMLResponse struct {
+	computedFieldsResponse
+}
+
+func (c computedFieldsHTMLResponse) HTML(r *http.Request) string {
+	return c.Renderer.(HTMLer).HTML(r)
+}
+
+// computedFieldsHTMLRendererResponse keeps the HTML response when computed fields are added to an HTMLRenderer
+type computedFieldsHTMLRendererResponse struct {
+	computedFieldsResponse
+}
+
+func (c computedFieldsHTMLRendererResponse) RenderHTML(r *http.Request) (string, error) {
+	return c.Renderer.(HTMLRenderer).RenderHTML(r)
+}
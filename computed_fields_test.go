@@ -0,0 +1,77 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputedFields(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	newAPI := func() *babyapi.API[*Album] {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithComputedFields(func(_ context.Context, a *Album) map[string]any {
+				return map[string]any{
+					"url":         "/albums/" + a.GetID(),
+					"title_count": len(a.Title),
+				}
+			})
+		require.NoError(t, api.Storage.Set(context.Background(), album))
+		return api
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, newAPI(), r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"id":"`+album.GetID()+`","title":"Album1","url":"/albums/`+album.GetID()+`","title_count":6}`, w.Body.String())
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, newAPI(), r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"items":[{"id":"`+album.GetID()+`","title":"Album1","url":"/albums/`+album.GetID()+`","title_count":6}]}`, w.Body.String())
+	})
+
+	t.Run("NotStored", func(t *testing.T) {
+		api := newAPI()
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+		require.NoError(t, err)
+		_ = babytest.TestRequest[*Album](t, api, r)
+
+		stored, err := api.Storage.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		require.Equal(t, album, stored)
+	})
+
+	t.Run("WithTimeFormat", func(t *testing.T) {
+		start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		event := &Event{DefaultResource: babyapi.NewDefaultResource(), Name: "Event1", Start: start}
+
+		api := babyapi.NewAPI("Events", "/events", func() *Event { return &Event{} }).
+			WithTimeFormat(babyapi.TimeFormatUnix).
+			WithComputedFields(func(_ context.Context, e *Event) map[string]any {
+				return map[string]any{"year": e.Start.Year()}
+			})
+		require.NoError(t, api.Storage.Set(context.Background(), event))
+
+		r, err := http.NewRequest(http.MethodGet, "/events/"+event.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Event](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"id":"`+event.GetID()+`","name":"Event1","start":1704164645,"schedule":{"times":null},"year":2024}`, w.Body.String())
+	})
+}
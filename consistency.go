@@ -0,0 +1,72 @@
+package babyapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// Consistency is the read consistency level requested by a client. Storage implementations for distributed
+// databases can use GetConsistencyFromContext in Get and GetAll to choose the consistency for each request.
+// Implementations that don't support tunable consistency can ignore it
+type Consistency string
+
+const (
+	// ConsistencyHeader is the request header used to set the Consistency
+	ConsistencyHeader = "X-Consistency"
+
+	ConsistencyEventual Consistency = "eventual"
+	ConsistencyStrong   Consistency = "strong"
+
+	// DefaultConsistency is used when the request does not set a Consistency
+	DefaultConsistency = ConsistencyEventual
+)
+
+// WithConsistencyHeader allows clients to request a Consistency with the X-Consistency header. The value is not case
+// sensitive and unknown values are rejected with 400 Bad Request. Without this option, the header is ignored. This
+// also applies to nested APIs
+func (a *API[T]) WithConsistencyHeader() *API[T] {
+	a.panicIfReadOnly()
+
+	a.consistencyHeader = true
+	return a
+}
+
+// GetConsistencyFromContext returns the Consistency requested with the X-Consistency header. If the header was not
+// set or WithConsistencyHeader is not used, DefaultConsistency is returned
+func GetConsistencyFromContext(ctx context.Context) Consistency {
+	consistency, ok := ctx.Value(consistencyCtxKey).(Consistency)
+	if !ok {
+		return DefaultConsistency
+	}
+	return consistency
+}
+
+// NewContextWithConsistency stores the Consistency in the context
+func NewContextWithConsistency(ctx context.Context, consistency Consistency) context.Context {
+	return context.WithValue(ctx, consistencyCtxKey, consistency)
+}
+
+// consistencyMiddleware reads the X-Consistency header into the request context and rejects unknown values
+func consistencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(ConsistencyHeader)
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		consistency := Consistency(strings.ToLower(header))
+		switch consistency {
+		case ConsistencyEventual, ConsistencyStrong:
+		default:
+			_ = render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid %s header %q", ConsistencyHeader, header)))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContextWithConsistency(r.Context(), consistency)))
+	})
+}
@@ -0,0 +1,77 @@
+package babyapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/go-chi/render"
+)
+
+// contentTypeNames are the media types used in the "accepted" field of 415 responses for the request content types
+// that can be decoded by render.Decode
+var contentTypeNames = map[render.ContentType]string{
+	render.ContentTypeJSON: "application/json",
+	render.ContentTypeXML:  "application/xml",
+	render.ContentTypeForm: "application/x-www-form-urlencoded",
+}
+
+// WithStrictContentType rejects POST, PUT, and PATCH requests with a body that has a missing or unsupported
+// Content-Type header. The response is 415 Unsupported Media Type with an "accepted" field that lists the content
+// types supported by this API. This also applies to nested APIs
+func (a *API[T]) WithStrictContentType() *API[T] {
+	a.panicIfReadOnly()
+
+	a.strictContentType = true
+	return a
+}
+
+// acceptedContentTypes returns the request content types that this API can decode. Field name transforms, time
+// formats, strict binding, and read-only fields are only applied to JSON, so other content types are not accepted
+// when the API uses them
+func (a *API[T]) acceptedContentTypes() []string {
+	jsonOnly := a.strictBinding || a.timeFormat != "" || a.fieldNameTransform != nil ||
+		(a.instance != nil && hasFieldAccessTags(reflect.TypeOf(a.instance())))
+	if jsonOnly {
+		return []string{contentTypeNames[render.ContentTypeJSON]}
+	}
+
+	return []string{
+		contentTypeNames[render.ContentTypeJSON],
+		contentTypeNames[render.ContentTypeXML],
+		contentTypeNames[render.ContentTypeForm],
+	}
+}
+
+func strictContentTypeMiddleware(accepted []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if slices.Contains(accepted, contentTypeNames[render.GetRequestContentType(r)]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			contentType := r.Header.Get("Content-Type")
+			err := fmt.Errorf("unsupported content type %q", contentType)
+			if contentType == "" {
+				err = fmt.Errorf("missing Content-Type header")
+			}
+
+			GetLoggerFromContext(r.Context()).Warn("rejecting request with unsupported content type", "content_type", contentType)
+			_ = render.Render(w, r, ErrUnsupportedMediaType(err, accepted))
+		})
+	}
+}
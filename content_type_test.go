@@ -0,0 +1,86 @@
+package babyapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictContentType(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"JSON", http.MethodPost, "application/json; charset=utf-8", `{"title":"Album1"}`, http.StatusCreated, ""},
+		{"XML", http.MethodPost, "application/xml", "<Album></Album>", http.StatusCreated, ""},
+		{
+			"Unsupported",
+			http.MethodPost,
+			"text/plain",
+			"Album1",
+			http.StatusUnsupportedMediaType,
+			`{"status":"Unsupported media type.","error_code":"unsupported_media_type","error":"unsupported content type \"text/plain\"","accepted":["application/json","application/xml","application/x-www-form-urlencoded"]}`,
+		},
+		{
+			"Missing",
+			http.MethodPut,
+			"",
+			`{"id":"cljcqg5o402e9s28rbp0","title":"Album1"}`,
+			http.StatusUnsupportedMediaType,
+			`{"status":"Unsupported media type.","error_code":"unsupported_media_type","error":"missing Content-Type header","accepted":["application/json","application/xml","application/x-www-form-urlencoded"]}`,
+		},
+		{"GetIgnored", http.MethodGet, "text/plain", "", http.StatusOK, ""},
+	}
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).WithStrictContentType()
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/albums"
+			if tt.method == http.MethodPut {
+				path += "/cljcqg5o402e9s28rbp0"
+			}
+
+			r := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedBody != "" {
+				require.Equal(t, tt.expectedBody, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+	t.Run("JSONOnlyAPI", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithStrictContentType().
+			WithStrictBinding()
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader("<Album></Album>"))
+		r.Header.Set("Content-Type", "application/xml")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusUnsupportedMediaType, w.Result().StatusCode)
+		require.Equal(
+			t,
+			`{"status":"Unsupported media type.","error_code":"unsupported_media_type","error":"unsupported content type \"application/xml\"","accepted":["application/json"]}`,
+			strings.TrimSpace(w.Body.String()),
+		)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
 )
 
 // ContextKey is used to store API resources in the request context
@@ -14,6 +17,16 @@ type ctxKey int
 const (
 	loggerCtxKey ctxKey = iota
 	requestBodyCtxKey
+	timeFormatCtxKey
+	devModeCtxKey
+	consistencyCtxKey
+	responseStatusCtxKey
+	fieldNameTransformCtxKey
+	jsonCodecCtxKey
+	fieldAccessCtxKey
+	fieldSelectionCtxKey
+	absoluteURLCtxKey
+	emptyBodyCtxKey
 )
 
 // GetLoggerFromContext returns the structured logger from the context. It expects to use an HTTP
@@ -32,6 +45,16 @@ func NewContextWithLogger(ctx context.Context, logger *slog.Logger) context.Cont
 	return context.WithValue(ctx, loggerCtxKey, logger)
 }
 
+// SetResponseStatus sets the status code used when rendering the response returned to Handler. This allows custom
+// route handlers to choose a status based on the result, like 202 when a job is queued. It has no effect when the
+// handler returns an *ErrResponse or the request is not handled by Handler
+func SetResponseStatus(r *http.Request, status int) {
+	statusPtr, ok := r.Context().Value(responseStatusCtxKey).(*int)
+	if ok {
+		*statusPtr = status
+	}
+}
+
 // GetRequestBodyFromContext gets an API resource from the request context. It can only be used in
 // URL paths that include the resource ID
 func GetRequestBodyFromContext[T any](ctx context.Context) (T, bool) {
@@ -79,3 +102,62 @@ func (a *API[T]) newContextWithResource(ctx context.Context, value T) context.Co
 func (a *API[T]) contextKey() ContextKey {
 	return ContextKey(a.name)
 }
+
+// notFoundContextKey is used to record that the requested resource was read from storage and does not exist, which is
+// different from not knowing if it exists because the read failed
+type notFoundContextKey ContextKey
+
+func (a *API[T]) newContextWithResourceNotFound(ctx context.Context) context.Context {
+	return context.WithValue(ctx, notFoundContextKey(a.contextKey()), true)
+}
+
+// resourceNotFoundInContext returns true if the requested resource is known to not exist before the request
+func (a *API[T]) resourceNotFoundInContext(ctx context.Context) bool {
+	notFound, _ := ctx.Value(notFoundContextKey(a.contextKey())).(bool)
+	return notFound
+}
+
+// ContextValueKey is a typed key for storing request-scoped values in the context, like an authenticated user's
+// organization ID that is used by a GetAll filter. Each key created with NewContextValueKey is unique, so keys from
+// different packages never collide even if they have the same name
+type ContextValueKey[V any] struct {
+	name string
+}
+
+// NewContextValueKey creates a new key for values of type V. The name is only used to describe the key
+func NewContextValueKey[V any](name string) *ContextValueKey[V] {
+	return &ContextValueKey[V]{name}
+}
+
+// String returns the key's name
+func (k *ContextValueKey[V]) String() string {
+	return k.name
+}
+
+// SetContextValue stores a value in the context using a typed key
+func SetContextValue[V any](ctx context.Context, key *ContextValueKey[V], value V) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// GetContextValue gets a value from the context using a typed key. It returns false if the value is not set
+func GetContextValue[V any](ctx context.Context, key *ContextValueKey[V]) (V, bool) {
+	value, ok := ctx.Value(key).(V)
+	return value, ok
+}
+
+// WithContextValue creates a middleware that uses the extractor to get a value from the request and stores it in the
+// request context with the key. Then, the value can be read by filters, hooks, and handlers using GetContextValue.
+// If the extractor returns an error, it is used as the response
+func WithContextValue[V any](key *ContextValueKey[V], extractor func(*http.Request) (V, *ErrResponse)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value, httpErr := extractor(r)
+			if httpErr != nil {
+				_ = render.Render(w, r, httpErr)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(SetContextValue(r.Context(), key, value)))
+		})
+	}
+}
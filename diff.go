@@ -0,0 +1,85 @@
+package babyapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// PreferMinimalChanges can be used in the Prefer header of a PATCH request to get a response with only the
+// fields that were changed by the request, plus the ID
+const PreferMinimalChanges = "return=minimal-changes"
+
+// ChangedFields is a response containing only the fields of a resource that were changed
+type ChangedFields map[string]any
+
+// Render implements render.Renderer
+func (ChangedFields) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// Diff compares the JSON representations of two values and returns the top-level fields that are different in the
+// after value. If a field was removed, it is included with a nil value. Nested objects are compared as a whole, so
+// the full nested object is included if any part of it changed
+func Diff(before, after any) (ChangedFields, error) {
+	beforeMap, err := toJSONMap(before)
+	if err != nil {
+		return nil, fmt.Errorf("error converting before value: %w", err)
+	}
+
+	afterMap, err := toJSONMap(after)
+	if err != nil {
+		return nil, fmt.Errorf("error converting after value: %w", err)
+	}
+
+	changed := ChangedFields{}
+	for key, afterValue := range afterMap {
+		beforeValue, ok := beforeMap[key]
+		if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			changed[key] = afterValue
+		}
+	}
+
+	for key := range beforeMap {
+		if _, ok := afterMap[key]; !ok {
+			changed[key] = nil
+		}
+	}
+
+	return changed, nil
+}
+
+// toJSONMap converts the input to a map using its JSON representation
+func toJSONMap(in any) (map[string]any, error) {
+	if m, ok := in.(map[string]any); ok {
+		return m, nil
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// preferMinimalChanges checks the Prefer header for PreferMinimalChanges
+func preferMinimalChanges(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.TrimSpace(preference) == PreferMinimalChanges {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   any
+		after    any
+		expected babyapi.ChangedFields
+	}{
+		{
+			"NoChanges",
+			map[string]any{"a": "b"},
+			map[string]any{"a": "b"},
+			babyapi.ChangedFields{},
+		},
+		{
+			"ChangedAddedAndRemoved",
+			map[string]any{"a": "b", "c": "d"},
+			map[string]any{"a": "x", "e": "f"},
+			babyapi.ChangedFields{"a": "x", "c": nil, "e": "f"},
+		},
+		{
+			"NestedChange",
+			map[string]any{"a": map[string]any{"b": "c", "d": "e"}},
+			map[string]any{"a": map[string]any{"b": "c", "d": "x"}},
+			babyapi.ChangedFields{"a": map[string]any{"b": "c", "d": "x"}},
+		},
+		{
+			"Structs",
+			&Album{Title: "Before"},
+			&Album{Title: "After"},
+			babyapi.ChangedFields{"title": "After"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := babyapi.Diff(tt.before, tt.after)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, changed)
+		})
+	}
+}
+
+func TestPatchMinimalChanges(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	require.NoError(t, api.Storage.Set(context.Background(), album))
+
+	tests := []struct {
+		name              string
+		prefer            string
+		body              string
+		expectedBody      string
+		expectedPreferred string
+	}{
+		{
+			"ChangedFieldsOnly",
+			babyapi.PreferMinimalChanges,
+			`{"title": "NewTitle"}`,
+			`{"id":"` + album.GetID() + `","title":"NewTitle"}`,
+			babyapi.PreferMinimalChanges,
+		},
+		{
+			"NoChanges",
+			"respond-async, " + babyapi.PreferMinimalChanges,
+			`{"title": "NewTitle"}`,
+			`{"id":"` + album.GetID() + `"}`,
+			babyapi.PreferMinimalChanges,
+		},
+		{
+			"FullResourceWithoutPreference",
+			"",
+			`{"title": "OtherTitle"}`,
+			`{"id":"` + album.GetID() + `","title":"OtherTitle"}`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPatch, "/albums/"+album.GetID(), bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.prefer != "" {
+				r.Header.Set("Prefer", tt.prefer)
+			}
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+			require.Equal(t, tt.expectedPreferred, w.Result().Header.Get("Preference-Applied"))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package babyapi
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 )
@@ -12,6 +13,33 @@ type EndDateable interface {
 	SetEndDate(time.Time)
 }
 
+// WithGoneForSoftDeleted responds with 410 Gone instead of the resource for GET requests when the resource implements
+// EndDateable and is end-dated. This lets clients tell the difference between resources that never existed and ones
+// that were deleted. Other methods are not affected, so deleting an end-dated resource still permanently deletes it
+func (a *API[T]) WithGoneForSoftDeleted() *API[T] {
+	a.panicIfReadOnly()
+
+	a.goneForSoftDeleted = true
+	return a
+}
+
+// isGone checks if the resource should be reported as gone for the request
+func (a *API[T]) isGone(r *http.Request, resource T) bool {
+	if !a.goneForSoftDeleted || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+
+	endDateable, ok := any(resource).(EndDateable)
+	return ok && endDateable.EndDated()
+}
+
+// isSoftDelete checks if deleting the resource will only set its end date. This is the behavior of KVStorage for
+// resources that implement EndDateable and are not already end-dated
+func isSoftDelete(resource any) bool {
+	endDateable, ok := resource.(EndDateable)
+	return ok && !endDateable.EndDated()
+}
+
 func EndDatedQueryParam(value bool) url.Values {
 	return url.Values{"end_dated": []string{fmt.Sprint(value)}}
 }
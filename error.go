@@ -7,18 +7,48 @@ import (
 	"github.com/go-chi/render"
 )
 
-var ErrNotFoundResponse = &ErrResponse{HTTPStatusCode: http.StatusNotFound, StatusText: "Resource not found."}
-var ErrMethodNotAllowedResponse = &ErrResponse{HTTPStatusCode: http.StatusMethodNotAllowed, StatusText: "Method not allowed."}
-var ErrForbidden = &ErrResponse{HTTPStatusCode: http.StatusForbidden, StatusText: "Forbidden"}
+// StatusClientClosedRequest is a non-standard status code used when the client disconnects before the response
+// is written
+const StatusClientClosedRequest = 499
+
+// Error codes are stable, machine-readable values for the Code field of ErrResponse so clients can handle errors
+// without parsing the messages
+const (
+	ErrCodeNotFound           = "not_found"
+	ErrCodeMethodNotAllowed   = "method_not_allowed"
+	ErrCodeGone               = "gone"
+	ErrCodePreconditionFailed = "precondition_failed"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeInvalidID          = "invalid_id"
+	ErrCodeNotAcceptable      = "not_acceptable"
+	ErrCodeUnsupportedMedia   = "unsupported_media_type"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeRender             = "render_error"
+	ErrCodeSerialization      = "serialization_error"
+	ErrCodeServiceUnavailable = "service_unavailable"
+	ErrCodeMaintenance        = "maintenance"
+)
+
+var ErrNotFoundResponse = &ErrResponse{HTTPStatusCode: http.StatusNotFound, StatusText: "Resource not found.", Code: ErrCodeNotFound}
+var ErrMethodNotAllowedResponse = &ErrResponse{HTTPStatusCode: http.StatusMethodNotAllowed, StatusText: "Method not allowed.", Code: ErrCodeMethodNotAllowed}
+var ErrGoneResponse = &ErrResponse{HTTPStatusCode: http.StatusGone, StatusText: "Resource is gone.", Code: ErrCodeGone}
+var ErrPreconditionFailedResponse = &ErrResponse{HTTPStatusCode: http.StatusPreconditionFailed, StatusText: "Precondition failed.", Code: ErrCodePreconditionFailed}
+var ErrForbidden = &ErrResponse{HTTPStatusCode: http.StatusForbidden, StatusText: "Forbidden", Code: ErrCodeForbidden}
+var ErrServiceUnavailableResponse = &ErrResponse{HTTPStatusCode: http.StatusServiceUnavailable, StatusText: "Service unavailable.", Code: ErrCodeServiceUnavailable}
+var ErrMaintenanceResponse = &ErrResponse{HTTPStatusCode: http.StatusServiceUnavailable, StatusText: "Service unavailable for maintenance.", Code: ErrCodeMaintenance}
 
 // ErrResponse is an error that implements Renderer to be used in HTTP response
 type ErrResponse struct {
 	Err            error `json:"-"`
 	HTTPStatusCode int   `json:"-"`
 
-	StatusText string `json:"status"`          // user-level status message
-	AppCode    int64  `json:"code,omitempty"`  // application-specific error code
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string `json:"status"`               // user-level status message
+	AppCode    int64  `json:"code,omitempty"`       // application-specific error code
+	Code       string `json:"error_code,omitempty"` // stable, machine-readable error code like ErrCodeNotFound
+	ErrorText  string `json:"error,omitempty"`      // application-level error message, for debugging
+
+	Accepted []string `json:"accepted,omitempty"` // supported request content types for 415 Unsupported Media Type
 }
 
 func (e *ErrResponse) Error() string {
@@ -35,15 +65,48 @@ func ErrRender(err error) *ErrResponse {
 		Err:            err,
 		HTTPStatusCode: 422,
 		StatusText:     "Error rendering response.",
+		Code:           ErrCodeRender,
 		ErrorText:      err.Error(),
 	}
 }
 
 func ErrInvalidRequest(err error) *ErrResponse {
+	return ErrInvalidRequestCoded(ErrCodeInvalidRequest, err)
+}
+
+// ErrInvalidRequestCoded creates a 400 Bad Request error with a custom error code so clients can tell different
+// validation errors apart
+func ErrInvalidRequestCoded(code string, err error) *ErrResponse {
 	return &ErrResponse{
 		Err:            err,
 		HTTPStatusCode: 400,
 		StatusText:     "Invalid request.",
+		Code:           code,
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrUnsupportedMediaType creates a 415 Unsupported Media Type error that lists the accepted content types so clients
+// can correct the request
+func ErrUnsupportedMediaType(err error, accepted []string) *ErrResponse {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnsupportedMediaType,
+		StatusText:     "Unsupported media type.",
+		Code:           ErrCodeUnsupportedMedia,
+		ErrorText:      err.Error(),
+		Accepted:       accepted,
+	}
+}
+
+// ErrNotAcceptable creates a 406 Not Acceptable error for requests that ask for a response content type that can't be
+// used for the resource
+func ErrNotAcceptable(err error) *ErrResponse {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusNotAcceptable,
+		StatusText:     "Not acceptable.",
+		Code:           ErrCodeNotAcceptable,
 		ErrorText:      err.Error(),
 	}
 }
@@ -53,6 +116,7 @@ func InternalServerError(err error) *ErrResponse {
 		Err:            err,
 		HTTPStatusCode: 500,
 		StatusText:     "Server Error.",
+		Code:           ErrCodeInternal,
 		ErrorText:      err.Error(),
 	}
 }
@@ -0,0 +1,113 @@
+package babyapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// VersionedStorage can be implemented by a Storage to provide a version that changes whenever a resource is created,
+// updated, or deleted. When ETags are enabled, it is used to create collection ETags without reading and hashing
+// every resource
+type VersionedStorage interface {
+	Version(context.Context) (string, error)
+}
+
+// WithETags enables ETag headers for GET and HEAD requests and responds with 304 Not Modified when the If-None-Match
+// header matches. Resources use a strong ETag created from their response. Collections use a weak ETag that is
+// created from the storage version if the Storage implements VersionedStorage. Otherwise, it is created from the
+// collection response
+func (a *API[T]) WithETags() *API[T] {
+	a.panicIfReadOnly()
+
+	a.etags = true
+	return a
+}
+
+// resourceETag creates a strong ETag from the response for a resource. It uses the same JSON as the response body, so
+// it changes with computed fields and options like WithTimeFormat, and the response content type
+func resourceETag(r *http.Request, resp render.Renderer) (string, error) {
+	data, err := representation(r, resp)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%q", hashETag(r, data)), nil
+}
+
+// representation is the JSON of the response after it is changed by the response options, which is used to create
+// ETags
+func representation(r *http.Request, resp render.Renderer) ([]byte, error) {
+	formatted, err := formatResponse(r, resp)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ETag: %w", err)
+	}
+
+	data, err := json.Marshal(formatted)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ETag: %w", err)
+	}
+
+	return data, nil
+}
+
+// collectionETag creates a weak ETag for a collection from a storage version or the collection response's JSON. The
+// query and response content type are included since they change the response
+func collectionETag(r *http.Request, data []byte) string {
+	return fmt.Sprintf("W/%q", hashETag(r, data))
+}
+
+func hashETag(r *http.Request, data []byte) string {
+	hash := sha256.New()
+	hash.Write(data)
+	hash.Write([]byte(r.URL.RawQuery))
+	hash.Write([]byte(fmt.Sprint(render.GetAcceptedContentType(r))))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// notModified sets the ETag header and responds with 304 Not Modified if it matches the If-None-Match header. It
+// returns true if the response was written
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// ifNoneMatchAny checks if the If-None-Match header is "*", which is used to only create a resource if it doesn't exist
+func ifNoneMatchAny(r *http.Request) bool {
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if strings.TrimSpace(candidate) == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// etagMatches uses weak comparison to check if any ETag in the If-None-Match header matches
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,195 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+// unversionedStorage hides the VersionedStorage implementation of the underlying Storage
+type unversionedStorage[T babyapi.Resource] struct {
+	babyapi.Storage[T]
+}
+
+func TestETags(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	request := func(t *testing.T, api *babyapi.API[*Album], method, path, ifNoneMatch, body string) *http.Response {
+		r, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		return babytest.TestRequest[*Album](t, api, r).Result()
+	}
+
+	storages := map[string]func() babyapi.Storage[*Album]{
+		"Versioned": func() babyapi.Storage[*Album] {
+			return babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		},
+		"Unversioned": func() babyapi.Storage[*Album] {
+			return unversionedStorage[*Album]{babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		},
+	}
+
+	for name, storage := range storages {
+		t.Run(name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				SetStorage(storage()).
+				WithETags()
+			require.NoError(t, api.Storage.Set(context.Background(), album))
+
+			t.Run("Resource", func(t *testing.T) {
+				resp := request(t, api, http.MethodGet, "/albums/"+album.GetID(), "", "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				etag := resp.Header.Get("ETag")
+				require.NotEmpty(t, etag)
+				require.NotContains(t, etag, "W/")
+
+				resp = request(t, api, http.MethodGet, "/albums/"+album.GetID(), etag, "")
+				require.Equal(t, http.StatusNotModified, resp.StatusCode)
+				require.Equal(t, etag, resp.Header.Get("ETag"))
+
+				resp = request(t, api, http.MethodGet, "/albums/"+album.GetID(), `"other", `+etag, "")
+				require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+				resp = request(t, api, http.MethodHead, "/albums/"+album.GetID(), "", "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, etag, resp.Header.Get("ETag"))
+
+				resp = request(t, api, http.MethodHead, "/albums/"+album.GetID(), etag, "")
+				require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+				resp = request(t, api, http.MethodPatch, "/albums/"+album.GetID(), "", `{"title": "NewTitle"}`)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				resp = request(t, api, http.MethodGet, "/albums/"+album.GetID(), etag, "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.NotEqual(t, etag, resp.Header.Get("ETag"))
+			})
+
+			t.Run("Collection", func(t *testing.T) {
+				resp := request(t, api, http.MethodGet, "/albums", "", "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				etag := resp.Header.Get("ETag")
+				require.Contains(t, etag, "W/")
+
+				resp = request(t, api, http.MethodGet, "/albums", etag, "")
+				require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+				resp = request(t, api, http.MethodGet, "/albums?title=Album1", etag, "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				resp = request(t, api, http.MethodPost, "/albums", "", `{"title": "Album2"}`)
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+				resp = request(t, api, http.MethodGet, "/albums", etag, "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.NotEqual(t, etag, resp.Header.Get("ETag"))
+			})
+		})
+	}
+
+	t.Run("ComputedFieldsChangeETag", func(t *testing.T) {
+		rating := 1
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithComputedFields(func(context.Context, *Album) map[string]any {
+				return map[string]any{"rating": rating}
+			}).
+			WithETags()
+		require.NoError(t, api.Storage.Set(context.Background(), album))
+
+		resp := request(t, api, http.MethodGet, "/albums/"+album.GetID(), "", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		etag := resp.Header.Get("ETag")
+
+		rating = 2
+		resp = request(t, api, http.MethodGet, "/albums/"+album.GetID(), etag, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotEqual(t, etag, resp.Header.Get("ETag"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+		require.NoError(t, api.Storage.Set(context.Background(), album))
+
+		resp := request(t, api, http.MethodGet, "/albums/"+album.GetID(), "*", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Empty(t, resp.Header.Get("ETag"))
+	})
+}
+
+func TestPutIfNoneMatchAny(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	require.NoError(t, api.Storage.Set(context.Background(), album))
+
+	newResource := babyapi.NewDefaultResource()
+	newID := newResource.GetID()
+
+	tests := []struct {
+		name           string
+		id             string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedTitle  string
+	}{
+		{"ExistingResource", album.GetID(), "*", http.StatusPreconditionFailed, "Album1"},
+		{"NewResource", newID, "*", http.StatusOK, "New"},
+		{"ExistingResourceWithoutHeader", album.GetID(), "", http.StatusOK, "New"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPut, "/albums/"+tt.id, bytes.NewBufferString(`{"id":"`+tt.id+`","title":"New"}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedStatus == http.StatusPreconditionFailed {
+				require.Equal(t, `{"status":"Precondition failed.","error_code":"precondition_failed"}`+"\n", w.Body.String())
+			}
+
+			stored, err := api.Storage.Get(context.Background(), tt.id)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedTitle, stored.Title)
+		})
+	}
+
+	t.Run("StorageError", func(t *testing.T) {
+		storage := &slowStorage{
+			Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+			release: make(chan struct{}),
+			err:     errors.New("storage error"),
+		}
+		close(storage.release)
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage)
+
+		r, err := http.NewRequest(http.MethodPut, "/albums/"+newID, bytes.NewBufferString(`{"id":"`+newID+`","title":"New"}`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("If-None-Match", "*")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+
+		_, err = storage.Storage.Get(context.Background(), newID)
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+}
@@ -25,7 +25,7 @@ func TestAPI(t *testing.T) {
 			},
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusBadRequest,
-				Body:   `{"status":"Invalid request.","error":"missing required 'password' field"}`,
+				Body:   `{"status":"Invalid request.","error_code":"invalid_request","error":"missing required 'password' field"}`,
 				Error:  "error posting resource: unexpected response with text: Invalid request.",
 			},
 		},
@@ -52,7 +52,7 @@ func TestAPI(t *testing.T) {
 			}),
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 			},
 		},
 		{
@@ -80,7 +80,7 @@ func TestAPI(t *testing.T) {
 			},
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 				Error:  "error getting all resources: unexpected response with text: Forbidden",
 			},
 		},
@@ -93,7 +93,7 @@ func TestAPI(t *testing.T) {
 			}),
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 			},
 		},
 		{
@@ -107,7 +107,7 @@ func TestAPI(t *testing.T) {
 			},
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 				Error:  "error getting resource: unexpected response with text: Forbidden",
 			},
 		},
@@ -125,7 +125,7 @@ func TestAPI(t *testing.T) {
 			},
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusBadRequest,
-				Body:   `{"status":"Invalid request.","error":"PUT not allowed"}`,
+				Body:   `{"status":"Invalid request.","error_code":"invalid_request","error":"PUT not allowed"}`,
 				Error:  "error putting resource: unexpected response with text: Invalid request.",
 			},
 		},
@@ -141,7 +141,7 @@ func TestAPI(t *testing.T) {
 			ClientName: "Invite",
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 				Error:  "error posting resource: unexpected response with text: Forbidden",
 			},
 		},
@@ -210,7 +210,7 @@ func TestAPI(t *testing.T) {
 			ClientName: "Invite",
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusForbidden,
-				Body:   `{"status":"Forbidden"}`,
+				Body:   `{"status":"Forbidden","error_code":"forbidden"}`,
 				Error:  "error getting all resources: unexpected response with text: Forbidden",
 			},
 		},
@@ -285,7 +285,7 @@ func TestAPI(t *testing.T) {
 			ExpectedResponse: babytest.ExpectedResponse{
 				Status: http.StatusMethodNotAllowed,
 				Error:  "error patching resource: unexpected response with text: Method not allowed.",
-				Body:   `{"status":"Method not allowed."}`,
+				Body:   `{"status":"Method not allowed.","error_code":"method_not_allowed"}`,
 			},
 		},
 	})
@@ -0,0 +1,109 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// FailoverStorage wraps an ordered list of Storage implementations. Each operation uses the first Storage that
+// doesn't return ErrStorageUnavailable. Other errors, including ErrNotFound, are returned without trying the next
+// Storage so a missing resource is not hidden by a secondary that has different data.
+//
+// FailoverStorage does not implement optional interfaces like VersionedStorage, CountingStorage, AggregatingStorage,
+// or ProjectingStorage even if the wrapped storages do, since each could have different data. The default handlers
+// use Get and GetAll instead
+type FailoverStorage[T Resource] struct {
+	storages []Storage[T]
+	writeAll bool
+}
+
+var _ Storage[*DefaultResource] = &FailoverStorage[*DefaultResource]{}
+
+// NewFailoverStorage creates a FailoverStorage that uses the primary Storage until it is unavailable, and then
+// tries each secondary in order
+func NewFailoverStorage[T Resource](primary Storage[T], secondary ...Storage[T]) *FailoverStorage[T] {
+	return &FailoverStorage[T]{storages: append([]Storage[T]{primary}, secondary...)}
+}
+
+// WriteToAll makes Set and Delete write to every Storage instead of only the first available one. Unavailable
+// storages are skipped, but the write fails if none are available or any returns a different error. Writes are not
+// atomic: when a Storage fails, the error is returned without writing to the rest, and the storages before it keep
+// the write
+func (s *FailoverStorage[T]) WriteToAll() *FailoverStorage[T] {
+	s.writeAll = true
+	return s
+}
+
+func (s *FailoverStorage[T]) Get(ctx context.Context, id string) (T, error) {
+	var result T
+	err := s.failover(func(storage Storage[T]) error {
+		var err error
+		result, err = storage.Get(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+func (s *FailoverStorage[T]) GetAll(ctx context.Context, query url.Values) ([]T, error) {
+	var result []T
+	err := s.failover(func(storage Storage[T]) error {
+		var err error
+		result, err = storage.GetAll(ctx, query)
+		return err
+	})
+	return result, err
+}
+
+func (s *FailoverStorage[T]) Set(ctx context.Context, resource T) error {
+	return s.write(func(storage Storage[T]) error {
+		return storage.Set(ctx, resource)
+	})
+}
+
+func (s *FailoverStorage[T]) Delete(ctx context.Context, id string) error {
+	return s.write(func(storage Storage[T]) error {
+		return storage.Delete(ctx, id)
+	})
+}
+
+// failover calls do with each Storage until one returns an error other than ErrStorageUnavailable
+func (s *FailoverStorage[T]) failover(do func(Storage[T]) error) error {
+	unavailable := []error{}
+	for _, storage := range s.storages {
+		err := do(storage)
+		if !errors.Is(err, ErrStorageUnavailable) {
+			return err
+		}
+		unavailable = append(unavailable, err)
+	}
+
+	return fmt.Errorf("all storages failed: %w", errors.Join(unavailable...))
+}
+
+func (s *FailoverStorage[T]) write(do func(Storage[T]) error) error {
+	if !s.writeAll {
+		return s.failover(do)
+	}
+
+	written := false
+	unavailable := []error{}
+	for _, storage := range s.storages {
+		err := do(storage)
+		if errors.Is(err, ErrStorageUnavailable) {
+			unavailable = append(unavailable, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		written = true
+	}
+
+	if !written {
+		return fmt.Errorf("all storages failed: %w", errors.Join(unavailable...))
+	}
+
+	return nil
+}
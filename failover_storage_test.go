@@ -0,0 +1,137 @@
+package babyapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type unavailableStorage struct{}
+
+func (unavailableStorage) Get(context.Context, string) (*Album, error) {
+	return nil, babyapi.ErrStorageUnavailable
+}
+
+func (unavailableStorage) GetAll(context.Context, url.Values) ([]*Album, error) {
+	return nil, babyapi.ErrStorageUnavailable
+}
+
+func (unavailableStorage) Set(context.Context, *Album) error {
+	return babyapi.ErrStorageUnavailable
+}
+
+func (unavailableStorage) Delete(context.Context, string) error {
+	return babyapi.ErrStorageUnavailable
+}
+
+// failingWriteStorage returns an error for every write
+type failingWriteStorage struct {
+	babyapi.Storage[*Album]
+}
+
+func (failingWriteStorage) Set(context.Context, *Album) error {
+	return errors.New("write error")
+}
+
+func TestFailoverStorage(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	t.Run("ReadsFromSecondaryWhenPrimaryUnavailable", func(t *testing.T) {
+		secondary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		require.NoError(t, secondary.Set(context.Background(), album))
+
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithStorageFailover(unavailableStorage{}, secondary)
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("NotFoundDoesNotFailOver", func(t *testing.T) {
+		primary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		secondary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		require.NoError(t, secondary.Set(context.Background(), album))
+
+		storage := babyapi.NewFailoverStorage[*Album](primary, secondary)
+		_, err := storage.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("AllUnavailable", func(t *testing.T) {
+		storage := babyapi.NewFailoverStorage[*Album](unavailableStorage{}, unavailableStorage{})
+
+		_, err := storage.GetAll(context.Background(), nil)
+		require.ErrorIs(t, err, babyapi.ErrStorageUnavailable)
+
+		err = storage.WriteToAll().Set(context.Background(), album)
+		require.ErrorIs(t, err, babyapi.ErrStorageUnavailable)
+	})
+
+	t.Run("WritesToFirstAvailable", func(t *testing.T) {
+		primary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		secondary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+
+		storage := babyapi.NewFailoverStorage[*Album](primary, secondary)
+		require.NoError(t, storage.Set(context.Background(), album))
+
+		_, err := primary.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		_, err = secondary.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("WriteToAll", func(t *testing.T) {
+		primary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		secondary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+
+		storage := babyapi.NewFailoverStorage[*Album](unavailableStorage{}, primary, secondary).WriteToAll()
+		require.NoError(t, storage.Set(context.Background(), album))
+
+		_, err := primary.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		_, err = secondary.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+
+		require.NoError(t, storage.Delete(context.Background(), album.GetID()))
+		_, err = secondary.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("WriteToAllNotAtomic", func(t *testing.T) {
+		primary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+		secondary := babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")
+
+		storage := babyapi.NewFailoverStorage[*Album](primary, failingWriteStorage{}, secondary).WriteToAll()
+		require.EqualError(t, storage.Set(context.Background(), album), "write error")
+
+		_, err := primary.Get(context.Background(), album.GetID())
+		require.NoError(t, err)
+		_, err = secondary.Get(context.Background(), album.GetID())
+		require.ErrorIs(t, err, babyapi.ErrNotFound)
+	})
+
+	t.Run("OptionalInterfacesNotForwarded", func(t *testing.T) {
+		primary := &countingStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		require.NoError(t, primary.Set(context.Background(), album))
+
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithStorageFailover(primary)
+
+		r, err := http.NewRequest(http.MethodHead, "/albums", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "1", w.Header().Get(babyapi.TotalCountHeader))
+	})
+}
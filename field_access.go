@@ -0,0 +1,196 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	// FieldAccessReadOnly is used with the `babyapi:"readonly"` struct tag for fields that are set by the server.
+	// They are removed from request bodies, or rejected when strict binding is enabled
+	FieldAccessReadOnly = "readonly"
+	// FieldAccessWriteOnly is used with the `babyapi:"writeonly"` struct tag for fields that can be set by clients, but
+	// are never included in responses, like passwords
+	FieldAccessWriteOnly = "writeonly"
+)
+
+// fieldAccessMiddleware enables removing write-only fields from responses. The tags are only enforced for JSON, so
+// requests that accept XML responses are rejected before they are handled
+func (a *API[T]) fieldAccessMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if render.GetAcceptedContentType(r) == render.ContentTypeXML {
+			GetLoggerFromContext(r.Context()).Warn("rejecting request for XML response with field access tags")
+
+			// The error is rendered as JSON since XML is not supported
+			render.Status(r, http.StatusNotAcceptable)
+			respondJSON(w, r, ErrNotAcceptable(errors.New("XML responses are not supported for resources with readonly or writeonly fields")))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), fieldAccessCtxKey, true)))
+	})
+}
+
+var fieldAccessTagsCache sync.Map
+
+// hasFieldAccessTags checks if the type or any nested types have fields with readonly or writeonly tags
+func hasFieldAccessTags(t reflect.Type) bool {
+	cached, ok := fieldAccessTagsCache.Load(t)
+	if ok {
+		return cached.(bool)
+	}
+
+	result := findFieldAccessTags(t, map[reflect.Type]bool{})
+	fieldAccessTagsCache.Store(t, result)
+	return result
+}
+
+func findFieldAccessTags(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || visited[t] || hasCustomJSON(t) {
+		return false
+	}
+	visited[t] = true
+
+	for _, field := range getJSONFields(t) {
+		if field.access != "" || findFieldAccessTags(field.typ, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkFieldAccessContentType rejects XML and form request bodies for resources with readonly or writeonly fields,
+// since the tags are only enforced for JSON
+func (a *API[T]) checkFieldAccessContentType(r *http.Request) *ErrResponse {
+	contentType := render.GetRequestContentType(r)
+	if contentType != render.ContentTypeXML && contentType != render.ContentTypeForm {
+		return nil
+	}
+
+	if !hasFieldAccessTags(reflect.TypeOf(a.instance())) {
+		return nil
+	}
+
+	return ErrUnsupportedMediaType(
+		fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type")),
+		a.acceptedContentTypes(),
+	)
+}
+
+// removeReadOnlyFields removes read-only fields from the decoded JSON request body. If reject is true, an error is
+// returned instead when the body has read-only fields
+func removeReadOnlyFields(target, data any, reject bool) error {
+	if !hasFieldAccessTags(reflect.TypeOf(target)) {
+		return nil
+	}
+
+	removed := removeFields(reflect.TypeOf(target), reflect.Value{}, data, FieldAccessReadOnly)
+	if len(removed) > 0 && reject {
+		return fmt.Errorf("read-only fields cannot be set: %s", strings.Join(removed, ", "))
+	}
+
+	return nil
+}
+
+// removeWriteOnlyFields removes write-only fields from the JSON data for the response value v
+func removeWriteOnlyFields(r *http.Request, v, data any) any {
+	if !fieldAccessEnabled(r) {
+		return data
+	}
+
+	value := reflect.ValueOf(v)
+	removeFields(value.Type(), value, data, FieldAccessWriteOnly)
+
+	return data
+}
+
+func fieldAccessEnabled(r *http.Request) bool {
+	enabled, _ := r.Context().Value(fieldAccessCtxKey).(bool)
+	return enabled
+}
+
+// removeFields walks the generic JSON data alongside the Go type it is decoded from or encoded to and deletes fields
+// with the access tag. It returns the names of removed fields
+func removeFields(t reflect.Type, v reflect.Value, data any, access string) []string {
+	t, v, ok := resolveJSONType(t, v)
+	if !ok || hasCustomJSON(t) {
+		return nil
+	}
+
+	removed := []string{}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for _, field := range getJSONFields(t) {
+			fieldData, ok := m[field.name]
+			if !ok {
+				continue
+			}
+
+			if field.access == access {
+				delete(m, field.name)
+				removed = append(removed, field.name)
+				continue
+			}
+
+			var fieldValue reflect.Value
+			if v.IsValid() {
+				fieldValue, _ = v.FieldByIndexErr(field.index)
+			}
+
+			for _, name := range removeFields(field.typ, fieldValue, fieldData, access) {
+				removed = append(removed, field.name+"."+name)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, ok := data.([]any)
+		if !ok {
+			return nil
+		}
+
+		for i := range s {
+			var elemValue reflect.Value
+			if v.IsValid() && i < v.Len() {
+				elemValue = v.Index(i)
+			}
+
+			for _, name := range removeFields(t.Elem(), elemValue, s[i], access) {
+				removed = append(removed, fmt.Sprintf("%d.%s", i, name))
+			}
+		}
+	case reflect.Map:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for key, elemData := range m {
+			var elemValue reflect.Value
+			if v.IsValid() && t.Key().Kind() == reflect.String {
+				elemValue = v.MapIndex(reflect.ValueOf(key).Convert(t.Key()))
+			}
+
+			for _, name := range removeFields(t.Elem(), elemValue, elemData, access) {
+				removed = append(removed, key+"."+name)
+			}
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,212 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type Credentials struct {
+	Password string `json:"password" babyapi:"writeonly"`
+	Hint     string `json:"hint"`
+}
+
+type Account struct {
+	babyapi.DefaultResource
+	Name        string        `json:"name"`
+	Status      string        `json:"status" babyapi:"readonly"`
+	Credentials []Credentials `json:"credentials"`
+}
+
+func TestFieldAccess(t *testing.T) {
+	account := &Account{
+		DefaultResource: babyapi.NewDefaultResource(),
+		Name:            "Account1",
+		Status:          "active",
+		Credentials:     []Credentials{{Password: "secret", Hint: "hint"}},
+	}
+
+	tests := []struct {
+		name           string
+		strict         bool
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			"GetMasksWriteOnly",
+			false,
+			http.MethodGet,
+			"/accounts/" + account.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + account.GetID() + `","name":"Account1","status":"active","credentials":[{"hint":"hint"}]}`,
+		},
+		{
+			"GetAllMasksWriteOnly",
+			false,
+			http.MethodGet,
+			"/accounts",
+			"",
+			http.StatusOK,
+			`{"items":[{"id":"` + account.GetID() + `","name":"Account1","status":"active","credentials":[{"hint":"hint"}]}]}`,
+		},
+		{
+			"PutIgnoresReadOnly",
+			false,
+			http.MethodPut,
+			"/accounts/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Account2","status":"admin","credentials":[{"password":"pw","hint":"h"}]}`,
+			http.StatusOK,
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Account2","status":"","credentials":[{"hint":"h"}]}`,
+		},
+		{
+			"PutStrictRejectsReadOnly",
+			true,
+			http.MethodPut,
+			"/accounts/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Account2","status":"admin"}`,
+			http.StatusBadRequest,
+			`{"status":"Invalid request.","error_code":"invalid_request","error":"read-only fields cannot be set: status"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Accounts", "/accounts", func() *Account { return &Account{} })
+			if tt.strict {
+				api.WithStrictBinding()
+			}
+			require.NoError(t, api.Storage.Set(context.Background(), account))
+
+			r, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Account](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			if tt.method == http.MethodPut && tt.expectedStatus == http.StatusOK {
+				stored, err := api.Storage.Get(context.Background(), "cljcqg5o402e9s28rbp0")
+				require.NoError(t, err)
+				require.Equal(t, "pw", stored.Credentials[0].Password)
+				require.Empty(t, stored.Status)
+			}
+		})
+	}
+}
+
+type User struct {
+	babyapi.DefaultResource
+	Name     string `json:"name"`
+	Password string `json:"password" babyapi:"writeonly"`
+}
+
+func (u *User) Patch(newUser *User) *babyapi.ErrResponse {
+	if newUser.Name != "" {
+		u.Name = newUser.Name
+	}
+	if newUser.Password != "" {
+		u.Password = newUser.Password
+	}
+	return nil
+}
+
+func TestFieldAccessMinimalChanges(t *testing.T) {
+	user := &User{DefaultResource: babyapi.NewDefaultResource(), Name: "User1", Password: "secret"}
+
+	api := babyapi.NewAPI("Users", "/users", func() *User { return &User{} })
+	require.NoError(t, api.Storage.Set(context.Background(), user))
+
+	r, err := http.NewRequest(http.MethodPatch, "/users/"+user.GetID(), bytes.NewBufferString(`{"name":"User2","password":"hunter2"}`))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Prefer", babyapi.PreferMinimalChanges)
+
+	w := babytest.TestRequest[*User](t, api, r)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Equal(t, babyapi.PreferMinimalChanges, w.Result().Header.Get("Preference-Applied"))
+	require.JSONEq(t, `{"id":"`+user.GetID()+`","name":"User2"}`, w.Body.String())
+
+	stored, err := api.Storage.Get(context.Background(), user.GetID())
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", stored.Password)
+}
+
+func TestFieldAccessNonJSON(t *testing.T) {
+	account := &Account{DefaultResource: babyapi.NewDefaultResource(), Name: "Account1", Status: "active"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		accept         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			"XMLBodyRejected",
+			http.MethodPost,
+			"/accounts",
+			"application/xml",
+			"",
+			"<Account><name>Account2</name><status>admin</status></Account>",
+			http.StatusUnsupportedMediaType,
+			`{"status":"Unsupported media type.","error_code":"unsupported_media_type","error":"unsupported content type \"application/xml\"","accepted":["application/json"]}`,
+		},
+		{
+			"FormBodyRejected",
+			http.MethodPost,
+			"/accounts",
+			"application/x-www-form-urlencoded",
+			"",
+			"name=Account2&status=admin",
+			http.StatusUnsupportedMediaType,
+			`{"status":"Unsupported media type.","error_code":"unsupported_media_type","error":"unsupported content type \"application/x-www-form-urlencoded\"","accepted":["application/json"]}`,
+		},
+		{
+			"XMLResponseNotAcceptable",
+			http.MethodGet,
+			"/accounts/" + account.GetID(),
+			"",
+			"application/xml",
+			"",
+			http.StatusNotAcceptable,
+			`{"status":"Not acceptable.","error_code":"not_acceptable","error":"XML responses are not supported for resources with readonly or writeonly fields"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Accounts", "/accounts", func() *Account { return &Account{} })
+			require.NoError(t, api.Storage.Set(context.Background(), account))
+
+			r, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			w := babytest.TestRequest[*Account](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			accounts, err := api.Storage.GetAll(context.Background(), nil)
+			require.NoError(t, err)
+			require.Len(t, accounts, 1)
+		})
+	}
+}
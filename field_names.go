@@ -0,0 +1,134 @@
+package babyapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+)
+
+// WithFieldNameTransform sets a function that is used to create JSON names for fields that do not have a json tag,
+// like converting "CreatedAt" to "created_at". The transform is applied to JSON responses and reversed when reading
+// JSON request bodies, so clients can use the transformed names without adding tags to every field. Fields with
+// explicit json tags are not changed. Nested APIs use the transform from their parent unless they set their own
+func (a *API[T]) WithFieldNameTransform(transform func(string) string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.fieldNameTransform = transform
+	return a
+}
+
+func (a *API[T]) fieldNameTransformMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), fieldNameTransformCtxKey, a.fieldNameTransform)))
+	})
+}
+
+func getFieldNameTransformFromContext(ctx context.Context) func(string) string {
+	transform, _ := ctx.Value(fieldNameTransformCtxKey).(func(string) string)
+	return transform
+}
+
+// transformResponseFields renames fields in the JSON data for the response value v using the transform from the
+// request context
+func transformResponseFields(r *http.Request, v, data any) any {
+	transform := getFieldNameTransformFromContext(r.Context())
+	if transform == nil {
+		return data
+	}
+
+	value := reflect.ValueOf(v)
+	return transformFieldNames(value.Type(), value, data, transform, false)
+}
+
+// transformRequestFields renames fields in the decoded JSON request body from their transformed names back to the
+// names expected by the target so the body can be bound normally
+func transformRequestFields(r *http.Request, target, data any) any {
+	transform := getFieldNameTransformFromContext(r.Context())
+	if transform == nil {
+		return data
+	}
+
+	return transformFieldNames(reflect.TypeOf(target), reflect.Value{}, data, transform, true)
+}
+
+// transformFieldNames walks the generic JSON data alongside the Go type it is decoded from or encoded to and renames
+// the keys for fields without a json tag. When input is true, transformed names are renamed to the Go field names.
+// Otherwise, Go field names are renamed to the transformed names. Keys that don't match a field are kept
+func transformFieldNames(t reflect.Type, v reflect.Value, data any, transform func(string) string, input bool) any {
+	t, v, ok := resolveJSONType(t, v)
+	if !ok || hasCustomJSON(t) {
+		return data
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+
+		result := make(map[string]any, len(m))
+		renamed := map[string]bool{}
+		for _, field := range getJSONFields(t) {
+			from, to := field.name, field.name
+			if !field.tagged {
+				from, to = field.name, transform(field.name)
+				if input {
+					from, to = to, from
+				}
+			}
+
+			fieldData, ok := m[from]
+			if !ok {
+				continue
+			}
+			renamed[from] = true
+
+			var fieldValue reflect.Value
+			if v.IsValid() {
+				fieldValue, _ = v.FieldByIndexErr(field.index)
+			}
+
+			result[to] = transformFieldNames(field.typ, fieldValue, fieldData, transform, input)
+		}
+
+		for key, value := range m {
+			_, exists := result[key]
+			if !renamed[key] && !exists {
+				result[key] = value
+			}
+		}
+
+		return result
+	case reflect.Slice, reflect.Array:
+		s, ok := data.([]any)
+		if !ok {
+			return data
+		}
+
+		for i := range s {
+			var elemValue reflect.Value
+			if v.IsValid() && i < v.Len() {
+				elemValue = v.Index(i)
+			}
+
+			s[i] = transformFieldNames(t.Elem(), elemValue, s[i], transform, input)
+		}
+	case reflect.Map:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+
+		for key, elemData := range m {
+			var elemValue reflect.Value
+			if v.IsValid() && t.Key().Kind() == reflect.String {
+				elemValue = v.MapIndex(reflect.ValueOf(key).Convert(t.Key()))
+			}
+
+			m[key] = transformFieldNames(t.Elem(), elemValue, elemData, transform, input)
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,134 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	StreetName string
+	PostalCode string `json:"zip"`
+}
+
+type Profile struct {
+	babyapi.DefaultResource
+	DisplayName string
+	CreatedAt   time.Time
+	Addresses   []Address
+	Nickname    string `json:"nick"`
+}
+
+func toSnakeCase(s string) string {
+	var result strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				result.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+func TestFieldNameTransform(t *testing.T) {
+	profile := &Profile{
+		DefaultResource: babyapi.NewDefaultResource(),
+		DisplayName:     "Profile1",
+		CreatedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Addresses:       []Address{{StreetName: "Main St", PostalCode: "12345"}},
+		Nickname:        "p1",
+	}
+
+	tests := []struct {
+		name         string
+		timeFormat   string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			"Get",
+			"",
+			http.MethodGet,
+			"/profiles/" + profile.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + profile.GetID() + `","display_name":"Profile1","created_at":"2024-01-02T03:04:05Z","addresses":[{"street_name":"Main St","zip":"12345"}],"nick":"p1"}`,
+		},
+		{
+			"GetWithTimeFormat",
+			babyapi.TimeFormatUnix,
+			http.MethodGet,
+			"/profiles/" + profile.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + profile.GetID() + `","display_name":"Profile1","created_at":1704164645,"addresses":[{"street_name":"Main St","zip":"12345"}],"nick":"p1"}`,
+		},
+		{
+			"GetAll",
+			"",
+			http.MethodGet,
+			"/profiles",
+			"",
+			http.StatusOK,
+			`{"items":[{"id":"` + profile.GetID() + `","display_name":"Profile1","created_at":"2024-01-02T03:04:05Z","addresses":[{"street_name":"Main St","zip":"12345"}],"nick":"p1"}]}`,
+		},
+		{
+			"Put",
+			"",
+			http.MethodPut,
+			"/profiles/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","display_name":"Profile2","created_at":"2024-01-02T03:04:05Z","addresses":[{"street_name":"Side St","zip":"54321"}],"nick":"p2"}`,
+			http.StatusOK,
+			`{"id":"cljcqg5o402e9s28rbp0","display_name":"Profile2","created_at":"2024-01-02T03:04:05Z","addresses":[{"street_name":"Side St","zip":"54321"}],"nick":"p2"}`,
+		},
+		{
+			"PutWithTimeFormat",
+			babyapi.TimeFormatUnix,
+			http.MethodPut,
+			"/profiles/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","display_name":"Profile2","created_at":1704164645}`,
+			http.StatusOK,
+			`{"id":"cljcqg5o402e9s28rbp0","display_name":"Profile2","created_at":1704164645,"addresses":null,"nick":""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Profiles", "/profiles", func() *Profile { return &Profile{} }).
+				WithFieldNameTransform(toSnakeCase)
+			if tt.timeFormat != "" {
+				api.WithTimeFormat(tt.timeFormat)
+			}
+			require.NoError(t, api.Storage.Set(context.Background(), profile))
+
+			r, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Profile](t, api, r)
+			require.Equal(t, tt.expectedCode, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			if tt.method == http.MethodPut {
+				stored, err := api.Storage.Get(context.Background(), "cljcqg5o402e9s28rbp0")
+				require.NoError(t, err)
+				require.Equal(t, "Profile2", stored.DisplayName)
+				require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), stored.CreatedAt.UTC())
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FieldsQueryParam is the query parameter used to select which fields are included in responses
+const FieldsQueryParam = "fields"
+
+// ProjectingStorage can be implemented by a Storage that is able to read only some fields of resources, like a
+// document database. It is used by the default GET handlers when fields are selected with the fields query parameter.
+// The fields are the JSON names from the request, and the returned resources only need to have those fields set
+type ProjectingStorage[T Resource] interface {
+	GetFields(ctx context.Context, id string, fields []string) (T, error)
+	GetAllFields(ctx context.Context, query url.Values, fields []string) ([]T, error)
+}
+
+// WithFieldSelection allows clients to select which fields are included in GET responses with a comma-separated
+// fields query parameter, like ?fields=id,title. For GetAll, the fields are selected for each item. If the Storage
+// implements ProjectingStorage, it is used to read only the selected fields. Otherwise, the full resources are read
+// and the response is projected
+func (a *API[T]) WithFieldSelection() *API[T] {
+	a.panicIfReadOnly()
+
+	a.fieldSelection = true
+	return a
+}
+
+func (a *API[T]) fieldSelectionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := parseSelectedFields(r)
+		if fields == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), fieldSelectionCtxKey, fields)))
+	})
+}
+
+// parseSelectedFields gets the fields from the query parameter for GET requests. It returns nil if no fields are
+// selected
+func parseSelectedFields(r *http.Request) []string {
+	if r.Method != http.MethodGet || !r.URL.Query().Has(FieldsQueryParam) {
+		return nil
+	}
+
+	fields := []string{}
+	for _, value := range r.URL.Query()[FieldsQueryParam] {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	return fields
+}
+
+// GetSelectedFieldsFromContext returns the fields selected by the fields query parameter. It returns nil if fields
+// are not selected
+func GetSelectedFieldsFromContext(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldSelectionCtxKey).([]string)
+	return fields
+}
+
+// projectingStorage returns the read storage as a ProjectingStorage and the selected fields if both are available.
+// Resources are not projected by storage when WithGoneForSoftDeleted needs the full resource to check its end date,
+// or when computed fields and links are created from the resource
+func (a *API[T]) projectingStorage(r *http.Request) (ProjectingStorage[T], []string, bool) {
+	fields := GetSelectedFieldsFromContext(r.Context())
+	if fields == nil || a.computedFields != nil || a.hateoas {
+		return nil, nil, false
+	}
+
+	if _, endDateable := any(*new(T)).(EndDateable); endDateable && a.goneForSoftDeleted {
+		return nil, nil, false
+	}
+
+	projecting, ok := a.getReadStorage().(ProjectingStorage[T])
+	return projecting, fields, ok
+}
+
+// getRequestedResourceFields is the same as GetRequestedResource, but only reads the selected fields if the Storage
+// implements ProjectingStorage. It is used by resourceExistsMiddleware so the resource is only read once
+func (a *API[T]) getRequestedResourceFields(r *http.Request) (T, *ErrResponse) {
+	projecting, fields, ok := a.projectingStorage(r)
+	if !ok {
+		return a.GetRequestedResource(r)
+	}
+
+	id := a.GetIDParam(r)
+	if id == "" {
+		return *new(T), ErrInvalidRequest(ErrMissingID)
+	}
+
+	resource, err := projecting.GetFields(r.Context(), a.storageKey(r, id), fields)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return *new(T), ErrNotFoundResponse
+		}
+
+		return *new(T), InternalServerError(err)
+	}
+
+	if a.isGone(r, resource) {
+		return *new(T), ErrGoneResponse
+	}
+
+	return resource, nil
+}
+
+// itemsResponse is implemented by responses that contain a list of resources in an items field, so field selection
+// applies to each item instead of the whole response
+type itemsResponse interface {
+	responseItems()
+}
+
+// projectResponseFields removes the fields that were not selected from the JSON data for the response value v
+func projectResponseFields(r *http.Request, v, data any) any {
+	fields := GetSelectedFieldsFromContext(r.Context())
+	if fields == nil {
+		return data
+	}
+
+	if _, ok := v.(*ErrResponse); ok {
+		return data
+	}
+
+	selected := map[string]bool{}
+	for _, field := range fields {
+		selected[field] = true
+	}
+
+	_, isList := v.(itemsResponse)
+	if !isList {
+		projectObject(data, selected)
+		return data
+	}
+
+	m, ok := data.(map[string]any)
+	if !ok {
+		return data
+	}
+
+	items, _ := m["items"].([]any)
+	for _, item := range items {
+		projectObject(item, selected)
+	}
+
+	return data
+}
+
+func projectObject(data any, selected map[string]bool) {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return
+	}
+
+	for key := range m {
+		if !selected[key] {
+			delete(m, key)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package babyapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type projectingStorage struct {
+	babyapi.Storage[*Album]
+	fields []string
+}
+
+func (s *projectingStorage) GetFields(ctx context.Context, id string, fields []string) (*Album, error) {
+	s.fields = fields
+	album, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &Album{DefaultResource: album.DefaultResource}, nil
+}
+
+func (s *projectingStorage) GetAllFields(ctx context.Context, query url.Values, fields []string) ([]*Album, error) {
+	s.fields = fields
+	albums, err := s.GetAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Album{}
+	for _, album := range albums {
+		result = append(result, &Album{DefaultResource: album.DefaultResource})
+	}
+	return result, nil
+}
+
+// softDeleteProjectingStorage only reads the ID when projecting, so the end date is not available
+type softDeleteProjectingStorage struct {
+	babyapi.Storage[*SoftDeleteAlbum]
+}
+
+func (s softDeleteProjectingStorage) GetFields(ctx context.Context, id string, _ []string) (*SoftDeleteAlbum, error) {
+	album, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &SoftDeleteAlbum{DefaultResource: album.DefaultResource}, nil
+}
+
+func (softDeleteProjectingStorage) GetAllFields(context.Context, url.Values, []string) ([]*SoftDeleteAlbum, error) {
+	return nil, nil
+}
+
+func TestFieldSelection(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithFieldSelection()
+	require.NoError(t, api.Storage.Set(context.Background(), album))
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{"GetAllFields", "/albums", `{"items":[{"id":"` + album.GetID() + `","title":"Album1"}]}`},
+		{"GetAllSelected", "/albums?fields=title", `{"items":[{"title":"Album1"}]}`},
+		{"GetSelected", "/albums/" + album.GetID() + "?fields=id", `{"id":"` + album.GetID() + `"}`},
+		{"GetMultipleSelected", "/albums/" + album.GetID() + "?fields=id,%20title", `{"id":"` + album.GetID() + `","title":"Album1"}`},
+		{"GetRepeatedParam", "/albums/" + album.GetID() + "?fields=id&fields=title", `{"id":"` + album.GetID() + `","title":"Album1"}`},
+		{"GetUnknownField", "/albums/" + album.GetID() + "?fields=other", `{}`},
+		{"NotFoundNotProjected", "/albums/DoesNotExist?fields=id", `{"status":"Resource not found.","error_code":"not_found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+
+	t.Run("ProjectingStorage", func(t *testing.T) {
+		storage := &projectingStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage).
+			WithFieldSelection()
+		require.NoError(t, storage.Set(context.Background(), album))
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID()+"?fields=id", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"id":"`+album.GetID()+`"}`, w.Body.String())
+		require.Equal(t, []string{"id"}, storage.fields)
+
+		r, err = http.NewRequest(http.MethodGet, "/albums?fields=id,title", http.NoBody)
+		require.NoError(t, err)
+
+		w = babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"items":[{"id":"`+album.GetID()+`","title":""}]}`, w.Body.String())
+		require.Equal(t, []string{"id", "title"}, storage.fields)
+	})
+
+	t.Run("ProjectingStorageWithComputedFields", func(t *testing.T) {
+		storage := &projectingStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage).
+			WithFieldSelection().
+			WithComputedFields(func(_ context.Context, a *Album) map[string]any {
+				return map[string]any{"title_length": len(a.Title)}
+			})
+		require.NoError(t, storage.Set(context.Background(), album))
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID()+"?fields=title_length", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, fmt.Sprintf(`{"title_length":%d}`, len(album.Title)), w.Body.String())
+		require.Nil(t, storage.fields)
+	})
+
+	t.Run("SoftDeletedGone", func(t *testing.T) {
+		endDate := time.Now().Add(-time.Hour)
+		album := &SoftDeleteAlbum{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1", EndDate: &endDate}
+
+		storage := softDeleteProjectingStorage{babyapi.NewKVStorage[*SoftDeleteAlbum](kv.NewDefaultDB(), "Albums")}
+		api := babyapi.NewAPI("Albums", "/albums", func() *SoftDeleteAlbum { return &SoftDeleteAlbum{} }).
+			SetStorage(storage).
+			WithFieldSelection().
+			WithGoneForSoftDeleted()
+		require.NoError(t, storage.Set(context.Background(), album))
+
+		r, err := http.NewRequest(http.MethodGet, "/albums/"+album.GetID()+"?fields=id", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*SoftDeleteAlbum](t, api, r)
+		require.Equal(t, http.StatusGone, w.Result().StatusCode)
+	})
+}
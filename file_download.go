@@ -0,0 +1,154 @@
+package babyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// BlobInfo describes a blob from a BlobStore. If ContentType is empty, it is detected from the Name's extension or the
+// blob's content. If Name is empty, the blob's key is used for the download's filename
+type BlobInfo struct {
+	Name        string
+	ContentType string
+	ModTime     time.Time
+}
+
+// BlobStore is used by WithFileDownload to read blobs that are referenced by resources. OpenBlob should return
+// ErrNotFound if the blob does not exist. The returned reader must be seekable so range requests can be served
+type BlobStore interface {
+	OpenBlob(ctx context.Context, key string) (io.ReadSeekCloser, BlobInfo, error)
+}
+
+// DirBlobStore is a BlobStore that reads blobs from files in a directory. The key is the file's path relative to the
+// directory
+type DirBlobStore string
+
+var _ BlobStore = DirBlobStore("")
+
+// OpenBlob opens the file from the directory
+func (d DirBlobStore) OpenBlob(_ context.Context, key string) (io.ReadSeekCloser, BlobInfo, error) {
+	if !fs.ValidPath(key) {
+		return nil, BlobInfo{}, fmt.Errorf("invalid blob key %q", key)
+	}
+
+	f, err := os.DirFS(string(d)).Open(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, BlobInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, BlobInfo{}, err
+	}
+	if stat.IsDir() {
+		f.Close()
+		return nil, BlobInfo{}, ErrNotFound
+	}
+
+	file, ok := f.(io.ReadSeekCloser)
+	if !ok {
+		f.Close()
+		return nil, BlobInfo{}, fmt.Errorf("file %q is not seekable", key)
+	}
+
+	return file, BlobInfo{Name: stat.Name(), ModTime: stat.ModTime()}, nil
+}
+
+// WithFileDownload adds a GET /base/{ID}/download route that streams the blob referenced by the resource's field from
+// the BlobStore. The field is referenced by its JSON name and must be a string containing the blob's key. Responses
+// include Content-Type and Content-Disposition headers and support range requests with 206 Partial Content
+func (a *API[T]) WithFileDownload(fieldName string, blobStore BlobStore) *API[T] {
+	a.panicIfReadOnly()
+
+	if a.instance == nil {
+		a.errors = append(a.errors, fmt.Errorf("WithFileDownload: cannot be used with a root API"))
+		return a
+	}
+
+	t := reflect.TypeOf(a.instance())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		a.errors = append(a.errors, fmt.Errorf("WithFileDownload: unable to get fields from resource type %s", t))
+		return a
+	}
+
+	fields := getJSONFields(t)
+	idx := slices.IndexFunc(fields, func(f jsonField) bool { return f.name == fieldName })
+	if idx < 0 {
+		a.errors = append(a.errors, fmt.Errorf("WithFileDownload: unknown field %q", fieldName))
+		return a
+	}
+
+	fieldType := fields[idx].typ
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.String {
+		a.errors = append(a.errors, fmt.Errorf("WithFileDownload: field %q must be a string", fieldName))
+		return a
+	}
+
+	return a.AddCustomIDRoute(http.MethodGet, "/download", a.defaultFileDownload(fields[idx].index, blobStore))
+}
+
+func (a *API[T]) defaultFileDownload(index []int, blobStore BlobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := GetLoggerFromContext(r.Context())
+
+		resource, err := a.GetResourceFromContext(r.Context())
+		if err != nil {
+			logger.Error("error getting resource from context", "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+
+		value := jsonFieldValue(resource, index)
+		if !value.IsValid() || value.String() == "" {
+			_ = render.Render(w, r, ErrNotFoundResponse)
+			return
+		}
+		key := value.String()
+
+		blob, info, err := blobStore.OpenBlob(r.Context(), key)
+		if errors.Is(err, ErrNotFound) {
+			_ = render.Render(w, r, ErrNotFoundResponse)
+			return
+		}
+		if err != nil {
+			logger.Error("error opening blob", "key", key, "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+		defer blob.Close()
+
+		name := info.Name
+		if name == "" {
+			name = path.Base(key)
+		}
+
+		if info.ContentType != "" {
+			w.Header().Set("Content-Type", info.ContentType)
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+
+		http.ServeContent(w, r, name, info.ModTime, blob)
+	}
+}
@@ -0,0 +1,80 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+type Track struct {
+	babyapi.DefaultResource
+	Title string `json:"title"`
+	File  string `json:"file"`
+}
+
+func TestFileDownload(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "song.txt"), []byte("0123456789"), 0o600))
+
+	api := babyapi.NewAPI("Tracks", "/tracks", func() *Track { return &Track{} }).
+		WithFileDownload("file", babyapi.DirBlobStore(dir))
+
+	track := &Track{DefaultResource: babyapi.NewDefaultResource(), Title: "Song", File: "song.txt"}
+	missing := &Track{DefaultResource: babyapi.NewDefaultResource(), Title: "Missing", File: "missing.txt"}
+	noFile := &Track{DefaultResource: babyapi.NewDefaultResource(), Title: "No File"}
+	for _, resource := range []*Track{track, missing, noFile} {
+		require.NoError(t, api.Storage.Set(context.Background(), resource))
+	}
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		id             string
+		rangeHeader    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"FullContent", track.GetID(), "", http.StatusOK, "0123456789"},
+		{"Range", track.GetID(), "bytes=2-5", http.StatusPartialContent, "2345"},
+		{"MissingBlob", missing.GetID(), "", http.StatusNotFound, `{"status":"Resource not found.","error_code":"not_found"}` + "\n"},
+		{"NoBlob", noFile.GetID(), "", http.StatusNotFound, `{"status":"Resource not found.","error_code":"not_found"}` + "\n"},
+		{"MissingResource", "cljcqg5o402e9s28rbp0", "", http.StatusNotFound, `{"status":"Resource not found.","error_code":"not_found"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/tracks/"+tt.id+"/download", http.NoBody)
+			if tt.rangeHeader != "" {
+				r.Header.Set("Range", tt.rangeHeader)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.Equal(t, tt.expectedBody, w.Body.String())
+
+			if tt.expectedStatus < http.StatusBadRequest {
+				require.Equal(t, "text/plain; charset=utf-8", w.Result().Header.Get("Content-Type"))
+				require.Equal(t, `attachment; filename=song.txt`, w.Result().Header.Get("Content-Disposition"))
+				require.Equal(t, "bytes", w.Result().Header.Get("Accept-Ranges"))
+			}
+		})
+	}
+
+	t.Run("InvalidField", func(t *testing.T) {
+		api := babyapi.NewAPI("Tracks", "/tracks", func() *Track { return &Track{} }).
+			WithFileDownload("missing", babyapi.DirBlobStore(dir))
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, `WithFileDownload: unknown field "missing"`)
+	})
+}
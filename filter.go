@@ -0,0 +1,128 @@
+package babyapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// FilterableField declares a query parameter that can be used to filter resources. When the query parameter
+// is present in the request, resources are only included if Value returns a matching string
+type FilterableField[T any] struct {
+	// Name is the query parameter key used to filter by this field
+	Name string
+	// Value gets the string representation of the field from a resource so it can be compared to the query parameter
+	Value func(T) string
+}
+
+// RepeatedParamPolicy controls how filters handle a query parameter that is repeated, like ?status=a&status=b
+type RepeatedParamPolicy int
+
+const (
+	// RepeatedParamIn includes resources that match any of the values, like status IN (a, b). This is the default
+	RepeatedParamIn RepeatedParamPolicy = iota
+	// RepeatedParamFirst only uses the first value and ignores the others
+	RepeatedParamFirst
+	// RepeatedParamError returns an error so the request fails with 400 Bad Request
+	RepeatedParamError
+)
+
+// ParseFilters uses the request's query parameters to create a FilterFunc for the provided fields. Query parameters
+// that don't match a FilterableField are ignored so they can still be used by Storage implementations or other
+// filters. Repeated query parameters match any of their values. This is used by the default GetAll handler and can be
+// used in custom routes to get consistent filtering
+func ParseFilters[T any](r *http.Request, fields []FilterableField[T]) (FilterFunc[T], error) {
+	return ParseFiltersWithPolicy(r, fields, RepeatedParamIn)
+}
+
+// ParseFiltersWithPolicy is the same as ParseFilters, but uses the provided policy for repeated query parameters
+func ParseFiltersWithPolicy[T any](r *http.Request, fields []FilterableField[T], policy RepeatedParamPolicy) (FilterFunc[T], error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	query, err := url.ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing query parameters: %w", err)
+	}
+
+	filters := []FilterFunc[T]{}
+	for _, field := range fields {
+		if !query.Has(field.Name) {
+			continue
+		}
+
+		if field.Value == nil {
+			return nil, fmt.Errorf("missing Value function for filter %q", field.Name)
+		}
+
+		expected := query[field.Name]
+		if len(expected) > 1 {
+			switch policy {
+			case RepeatedParamFirst:
+				expected = expected[:1]
+			case RepeatedParamError:
+				return nil, fmt.Errorf("query parameter %q can only be used once", field.Name)
+			}
+		}
+
+		value := field.Value
+		filters = append(filters, func(item T) bool {
+			return slices.Contains(expected, value(item))
+		})
+	}
+
+	return allFilters(filters...), nil
+}
+
+// allFilters combines FilterFuncs so an item is only included if it passes all of them. nil filters are skipped
+func allFilters[T any](filters ...FilterFunc[T]) FilterFunc[T] {
+	nonNil := []FilterFunc[T]{}
+	for _, f := range filters {
+		if f != nil {
+			nonNil = append(nonNil, f)
+		}
+	}
+
+	if len(nonNil) == 0 {
+		return nil
+	}
+
+	return func(item T) bool {
+		for _, f := range nonNil {
+			if !f(item) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// SetFilterableFields declares which query parameters can be used to filter resources in the default GetAll handler.
+// The filters are applied in addition to any filter set by SetGetAllFilter
+func (a *API[T]) SetFilterableFields(fields ...FilterableField[T]) *API[T] {
+	a.panicIfReadOnly()
+
+	a.filterableFields = append(a.filterableFields, fields...)
+	return a
+}
+
+// WithRepeatedParamPolicy sets how FilterableFields handle repeated query parameters in the default GetAll handler.
+// The default is RepeatedParamIn
+func (a *API[T]) WithRepeatedParamPolicy(policy RepeatedParamPolicy) *API[T] {
+	a.panicIfReadOnly()
+
+	a.repeatedParamPolicy = policy
+	return a
+}
+
+// getAllFilterFunc combines the FilterableFields and custom GetAll filter for the request
+func (a *API[T]) getAllFilterFunc(r *http.Request) (FilterFunc[T], error) {
+	filter, err := ParseFiltersWithPolicy[T](r, a.filterableFields, a.repeatedParamPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return allFilters(filter, a.getAllFilter(r)), nil
+}
@@ -0,0 +1,167 @@
+package babyapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+var albumTitleFilter = babyapi.FilterableField[*Album]{
+	Name:  "title",
+	Value: func(a *Album) string { return a.Title },
+}
+
+func TestParseFilters(t *testing.T) {
+	album1 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	album2 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album2"}
+
+	tests := []struct {
+		name          string
+		query         string
+		fields        []babyapi.FilterableField[*Album]
+		expected      []*Album
+		expectedError string
+	}{
+		{
+			"NoFields",
+			"title=Album1",
+			nil,
+			[]*Album{album1, album2},
+			"",
+		},
+		{
+			"NoMatchingQueryParams",
+			"other=value",
+			[]babyapi.FilterableField[*Album]{albumTitleFilter},
+			[]*Album{album1, album2},
+			"",
+		},
+		{
+			"FilterByTitle",
+			"title=Album1",
+			[]babyapi.FilterableField[*Album]{albumTitleFilter},
+			[]*Album{album1},
+			"",
+		},
+		{
+			"FilterByTitleNoResults",
+			"title=Album3",
+			[]babyapi.FilterableField[*Album]{albumTitleFilter},
+			[]*Album{},
+			"",
+		},
+		{
+			"RepeatedParamMatchesAny",
+			"title=Album1&title=Album2",
+			[]babyapi.FilterableField[*Album]{albumTitleFilter},
+			[]*Album{album1, album2},
+			"",
+		},
+		{
+			"InvalidQuery",
+			"title=%zz",
+			[]babyapi.FilterableField[*Album]{albumTitleFilter},
+			nil,
+			`error parsing query parameters: invalid URL escape "%zz"`,
+		},
+		{
+			"MissingValueFunc",
+			"title=Album1",
+			[]babyapi.FilterableField[*Album]{{Name: "title"}},
+			nil,
+			`missing Value function for filter "title"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/albums?"+tt.query, http.NoBody)
+
+			filter, err := babyapi.ParseFilters(r, tt.fields)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				require.Equal(t, tt.expectedError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expected, filter.Filter([]*Album{album1, album2}))
+		})
+	}
+}
+
+func TestFilterableFields(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		SetFilterableFields(albumTitleFilter)
+
+	album1 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	album2 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album2"}
+	require.NoError(t, api.Storage.Set(context.Background(), album1))
+	require.NoError(t, api.Storage.Set(context.Background(), album2))
+
+	client, stop := babytest.NewTestClient[*Album](t, api)
+	defer stop()
+
+	t.Run("FilterByTitle", func(t *testing.T) {
+		albums, err := client.GetAll(context.Background(), "title=Album2")
+		require.NoError(t, err)
+		require.Equal(t, []*Album{album2}, albums.Data.Items)
+	})
+
+	t.Run("NoFilter", func(t *testing.T) {
+		albums, err := client.GetAll(context.Background(), "")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*Album{album1, album2}, albums.Data.Items)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		_, err := client.GetAll(context.Background(), "title=%zz")
+		require.Error(t, err)
+		require.Equal(t, "error getting all resources: unexpected response with text: Invalid request.", err.Error())
+	})
+}
+
+func TestRepeatedParamPolicy(t *testing.T) {
+	album1 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	album2 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album2"}
+
+	tests := []struct {
+		name           string
+		policy         babyapi.RepeatedParamPolicy
+		expectedStatus int
+		expected       []*Album
+	}{
+		{"In", babyapi.RepeatedParamIn, http.StatusOK, []*Album{album1, album2}},
+		{"First", babyapi.RepeatedParamFirst, http.StatusOK, []*Album{album2}},
+		{"Error", babyapi.RepeatedParamError, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				SetFilterableFields(albumTitleFilter).
+				WithRepeatedParamPolicy(tt.policy)
+			require.NoError(t, api.Storage.Set(context.Background(), album1))
+			require.NoError(t, api.Storage.Set(context.Background(), album2))
+
+			r := httptest.NewRequest(http.MethodGet, "/albums?title=Album2&title=Album1", http.NoBody)
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+
+			if tt.expected == nil {
+				require.Contains(t, w.Body.String(), `query parameter \"title\" can only be used once`)
+				return
+			}
+
+			var resp babyapi.ResourceList[*Album]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.ElementsMatch(t, tt.expected, resp.Items)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package babyapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// LinksField is the response field with HATEOAS links when WithHATEOAS is used
+const LinksField = "_links"
+
+// Linker can be implemented by a Resource to add custom relations to its HATEOAS links
+type Linker interface {
+	Links(*http.Request) map[string]string
+}
+
+// WithHATEOAS adds HATEOAS links to the "_links" field of resource responses, including the items in GetAll
+// responses. The links are created from the API's routes, including the parent segments for nested APIs: "self",
+// "edit" and "delete" when PUT/PATCH and DELETE are enabled, "collection", and the collection of each nested API by
+// name. Resources can implement Linker to add or replace links. The fields are added the same way as computed fields.
+// Use WithAbsoluteURLs for absolute links
+func (a *API[T]) WithHATEOAS() *API[T] {
+	a.panicIfReadOnly()
+
+	a.hateoas = true
+	return a
+}
+
+// addLinks returns a copy of fields with the HATEOAS links for the resource
+func (a *API[T]) addLinks(r *http.Request, resource T, fields map[string]any) map[string]any {
+	result := make(map[string]any, len(fields)+1)
+	for key, value := range fields {
+		result[key] = value
+	}
+
+	collection := AbsoluteURL(r, collectionPath(a, r))
+	self := collection + "/" + resource.GetID()
+
+	links := map[string]string{
+		"self":       self,
+		"collection": collection,
+	}
+	if a.Put != nil || a.Patch != nil {
+		links["edit"] = self
+	}
+	if a.Delete != nil {
+		links["delete"] = self
+	}
+
+	for _, child := range a.subAPIs {
+		links[child.Name()] = self + child.Base()
+	}
+
+	linker, ok := any(resource).(Linker)
+	if ok {
+		for name, link := range linker.Links(r) {
+			links[name] = link
+		}
+	}
+
+	result[LinksField] = links
+	return result
+}
+
+// collectionPath creates the URL path for an API's collection using the IDs of parent resources from the request
+func collectionPath(api RelatedAPI, r *http.Request) string {
+	path := api.Base()
+	for parent := api.Parent(); parent != nil; parent = parent.Parent() {
+		if rel, ok := parent.(relatedAPI); ok && rel.isRoot() {
+			return strings.TrimSuffix(parent.Base(), "/") + path
+		}
+
+		path = parent.Base() + "/" + parent.GetIDParam(r) + path
+	}
+
+	return path
+}
@@ -0,0 +1,87 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type LinkedSong struct {
+	babyapi.DefaultResource
+	Title string `json:"title"`
+}
+
+func (s *LinkedSong) Links(*http.Request) map[string]string {
+	return map[string]string{"lyrics": "/lyrics/" + s.GetID()}
+}
+
+func TestHATEOAS(t *testing.T) {
+	artist := &Artist{DefaultResource: babyapi.NewDefaultResource(), Name: "Artist1"}
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	song := &LinkedSong{DefaultResource: babyapi.NewDefaultResource(), Title: "Song1"}
+
+	artistAPI := babyapi.NewAPI("Artists", "/artists", func() *Artist { return &Artist{} }).WithHATEOAS()
+	albumAPI := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).WithHATEOAS()
+	albumAPI.Delete = nil
+	songAPI := babyapi.NewAPI("Songs", "/songs", func() *LinkedSong { return &LinkedSong{} }).WithHATEOAS()
+	artistAPI.AddNestedAPI(albumAPI)
+	albumAPI.AddNestedAPI(songAPI)
+
+	require.NoError(t, artistAPI.Storage.Set(context.Background(), artist))
+	require.NoError(t, albumAPI.Storage.Set(context.Background(), album))
+	require.NoError(t, songAPI.Storage.Set(context.Background(), song))
+
+	artistPath := "/artists/" + artist.GetID()
+	albumPath := artistPath + "/albums/" + album.GetID()
+	songPath := albumPath + "/songs/" + song.GetID()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedBody string
+	}{
+		{
+			"Get",
+			artistPath,
+			`{"id":"` + artist.GetID() + `","name":"Artist1","_links":{
+				"self":"` + artistPath + `","edit":"` + artistPath + `","delete":"` + artistPath + `",
+				"collection":"/artists","Albums":"` + artistPath + `/albums"}}`,
+		},
+		{
+			"NestedWithoutDelete",
+			albumPath,
+			`{"id":"` + album.GetID() + `","title":"Album1","_links":{
+				"self":"` + albumPath + `","edit":"` + albumPath + `",
+				"collection":"` + artistPath + `/albums","Songs":"` + albumPath + `/songs"}}`,
+		},
+		{
+			"GetAll",
+			artistPath + "/albums",
+			`{"items":[{"id":"` + album.GetID() + `","title":"Album1","_links":{
+				"self":"` + albumPath + `","edit":"` + albumPath + `",
+				"collection":"` + artistPath + `/albums","Songs":"` + albumPath + `/songs"}}]}`,
+		},
+		{
+			"CustomLinks",
+			songPath,
+			`{"id":"` + song.GetID() + `","title":"Song1","_links":{
+				"self":"` + songPath + `","edit":"` + songPath + `","delete":"` + songPath + `",
+				"collection":"` + albumPath + `/songs","lyrics":"/lyrics/` + song.GetID() + `"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Artist](t, artistAPI, r)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
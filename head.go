@@ -0,0 +1,85 @@
+package babyapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/render"
+)
+
+// TotalCountHeader is the response header with the number of resources for a HEAD request to the collection
+const TotalCountHeader = "Total-Count"
+
+// CountingStorage can be implemented by a Storage that is able to count resources natively. It is used for HEAD
+// requests to the collection instead of reading all resources. The query contains the request's query parameters so
+// the Storage is responsible for applying any filters. It is not used when FilterableFields or the GetAll filter
+// apply to the request since they can only be applied to resources read from storage
+type CountingStorage interface {
+	Count(ctx context.Context, query url.Values) (int, error)
+}
+
+// defaultHeadAll responds to HEAD requests for the collection with the number of resources in the Total-Count header.
+// The same filters as GetAll are applied before counting
+func (a *API[T]) defaultHeadAll() http.HandlerFunc {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		logger := GetLoggerFromContext(r.Context())
+
+		filter, err := a.getAllFilterFunc(r)
+		if err != nil {
+			logger.Error("error parsing filters", "error", err)
+			return ErrInvalidRequest(err)
+		}
+
+		var count int
+		counter, ok := a.getReadStorage().(CountingStorage)
+		if ok && filter == nil {
+			count, err = counter.Count(r.Context(), r.URL.Query())
+			if err != nil {
+				logger.Error("error counting resources", "error", err)
+				return InternalServerError(err)
+			}
+		} else {
+			resources, err := a.getReadStorage().GetAll(r.Context(), r.URL.Query())
+			if err != nil {
+				logger.Error("error getting resources", "error", err)
+				return InternalServerError(err)
+			}
+
+			count = len(filter.Filter(resources))
+		}
+
+		w.Header().Set(TotalCountHeader, strconv.Itoa(count))
+		w.WriteHeader(a.responseCodes[MethodGetAll])
+
+		return nil
+	})
+}
+
+// defaultHead responds to HEAD requests for a resource. The resource is already read by resourceExistsMiddleware, so
+// it is only used to set the same ETag as a GET request
+func (a *API[T]) defaultHead() http.HandlerFunc {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		if a.etags {
+			resource, err := a.GetResourceFromContext(r.Context())
+			if err != nil {
+				return InternalServerError(err)
+			}
+
+			etag, err := resourceETag(r, a.wrapResponse(r, resource))
+			if err != nil {
+				GetLoggerFromContext(r.Context()).Error("error creating ETag", "error", err)
+				return InternalServerError(err)
+			}
+
+			if notModified(w, r, etag) {
+				return nil
+			}
+		}
+
+		w.WriteHeader(a.responseCodes[http.MethodGet])
+
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type countingStorage struct {
+	babyapi.Storage[*Album]
+	query url.Values
+}
+
+func (s *countingStorage) Count(_ context.Context, query url.Values) (int, error) {
+	s.query = query
+	return 42, nil
+}
+
+func TestHead(t *testing.T) {
+	album1 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	album2 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album2"}
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		SetFilterableFields(albumTitleFilter)
+	require.NoError(t, api.Storage.Set(context.Background(), album1))
+	require.NoError(t, api.Storage.Set(context.Background(), album2))
+
+	tests := []struct {
+		name               string
+		path               string
+		expectedStatus     int
+		expectedTotalCount string
+	}{
+		{"Collection", "/albums", http.StatusOK, "2"},
+		{"CollectionFiltered", "/albums?title=Album1", http.StatusOK, "1"},
+		{"Resource", "/albums/" + album1.GetID(), http.StatusOK, ""},
+		{"ResourceNotFound", "/albums/DoesNotExist", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodHead, tt.path, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.Equal(t, tt.expectedTotalCount, w.Header().Get(babyapi.TotalCountHeader))
+		})
+	}
+
+	t.Run("CountingStorage", func(t *testing.T) {
+		storage := &countingStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage)
+
+		r, err := http.NewRequest(http.MethodHead, "/albums?title=Album1", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "42", w.Header().Get(babyapi.TotalCountHeader))
+		require.Equal(t, "Album1", storage.query.Get("title"))
+	})
+
+	t.Run("CountingStorageWithGetAllFilter", func(t *testing.T) {
+		storage := &countingStorage{Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums")}
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage).
+			SetGetAllFilter(func(*http.Request) babyapi.FilterFunc[*Album] {
+				return func(a *Album) bool { return a.Title == "Album1" }
+			})
+		require.NoError(t, api.Storage.Set(context.Background(), album1))
+		require.NoError(t, api.Storage.Set(context.Background(), album2))
+
+		r, err := http.NewRequest(http.MethodHead, "/albums", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "1", w.Header().Get(babyapi.TotalCountHeader))
+		require.Nil(t, storage.query)
+	})
+}
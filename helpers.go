@@ -1,12 +1,16 @@
 package babyapi
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -28,6 +32,12 @@ func GetIDParamFromCtx(ctx context.Context, name string) string {
 	return chi.URLParamFromCtx(ctx, IDParamKey(name))
 }
 
+// GetPathParam gets any URL parameter from the request's path by key. This is useful for custom routes that have
+// more URL parameters than just the resource ID, like /base/{ID}/sub/{subID}
+func GetPathParam(r *http.Request, key string) string {
+	return chi.URLParam(r, key)
+}
+
 // IDParamKey gets the chi URL param key used for this API
 func (a *API[T]) IDParamKey() string {
 	return IDParamKey(a.name)
@@ -147,7 +157,7 @@ func (a *API[T]) ReadRequestBodyAndDo(do func(http.ResponseWriter, *http.Request
 			return nil
 		}
 
-		return a.responseWrapper(resp)
+		return a.wrapResponse(r, resp)
 	})
 }
 
@@ -170,7 +180,225 @@ func ReadRequestBodyAndDo[T RendererBinder](do func(http.ResponseWriter, *http.R
 
 // GetFromRequest will read the API's resource type from the request body or request context
 func (a *API[T]) GetFromRequest(r *http.Request) (T, *ErrResponse) {
-	return GetFromRequest(r, a.instance)
+	emptyBody := a.allowEmptyBody && r.Method == http.MethodPost && isEmptyBody(r)
+	if emptyBody {
+		r = r.WithContext(context.WithValue(r.Context(), emptyBodyCtxKey, true))
+	}
+
+	if _, ok := GetRequestBodyFromContext[T](r.Context()); ok {
+		return GetFromRequest(r, a.instance)
+	}
+
+	var body any
+	if !emptyBody {
+		httpErr := a.checkFieldAccessContentType(r)
+		if httpErr != nil {
+			return *new(T), httpErr
+		}
+
+		err := checkJSONObject(r)
+		if err != nil {
+			return *new(T), ErrInvalidRequest(err)
+		}
+
+		body, err = a.prepareRequestBody(r)
+		if err != nil {
+			return *new(T), ErrInvalidRequest(err)
+		}
+	}
+
+	resource, httpErr := GetFromRequest(r, a.instance)
+	if httpErr != nil {
+		return *new(T), httpErr
+	}
+
+	if r.Method == http.MethodPost {
+		a.applyDefaults(resource, body)
+	}
+
+	return resource, nil
+}
+
+// applyDefaults copies default values from a new Defaultable instance to the resource's fields that were omitted from
+// the decoded request body. Fields that are already set, like IDs from Bind, are not changed. If the body was not
+// decoded, like for form bodies, defaults are applied to all fields with zero values
+func (a *API[T]) applyDefaults(resource T, body any) {
+	defaults := a.instance()
+	defaultable, ok := any(defaults).(Defaultable)
+	if !ok {
+		return
+	}
+	defaultable.ApplyDefaults()
+
+	dst, src := reflect.ValueOf(resource), reflect.ValueOf(defaults)
+	for dst.Kind() == reflect.Pointer && !dst.IsNil() {
+		dst, src = dst.Elem(), src.Elem()
+	}
+	if dst.Kind() != reflect.Struct {
+		return
+	}
+
+	bodyFields, _ := body.(map[string]any)
+	for _, field := range getJSONFields(dst.Type()) {
+		if _, ok := bodyFields[field.name]; ok {
+			continue
+		}
+
+		dstField, err := dst.FieldByIndexErr(field.index)
+		if err != nil || !dstField.CanSet() || !dstField.IsZero() {
+			continue
+		}
+
+		srcField, err := src.FieldByIndexErr(field.index)
+		if err != nil || srcField.IsZero() {
+			continue
+		}
+
+		dstField.Set(srcField)
+	}
+}
+
+// isEmptyBody checks if the request has no body or only whitespace. A body that can't be read is not empty so the
+// error comes from binding
+func isEmptyBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	first, err := peekJSON(r)
+	return err == nil && first == 0
+}
+
+// checkJSONObject returns a clear error if a JSON request body is not an object, like when an array is sent to an
+// endpoint that expects a single resource
+func checkJSONObject(r *http.Request) error {
+	if render.GetRequestContentType(r) != render.ContentTypeJSON {
+		return nil
+	}
+
+	first, err := peekJSON(r)
+	if err != nil {
+		return fmt.Errorf("error reading request body: %w", err)
+	}
+
+	switch first {
+	case '[':
+		return errors.New("expected a JSON object but got an array")
+	case '"':
+		return errors.New("expected a JSON object but got a string")
+	case 't', 'f':
+		return errors.New("expected a JSON object but got a boolean")
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return errors.New("expected a JSON object but got a number")
+	default:
+		// Objects, null, empty bodies, and invalid JSON are left for binding to handle
+		return nil
+	}
+}
+
+// peekJSON returns the first non-whitespace byte of the request body without consuming it. It returns 0 if the
+// body is empty. The leading whitespace is kept so the body is not changed, no matter how much there is
+func peekJSON(r *http.Request) (byte, error) {
+	if r.Body == nil {
+		return 0, nil
+	}
+
+	body := r.Body
+	reader := bufio.NewReader(body)
+	whitespace := []byte{}
+	defer func() {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(whitespace), reader), body}
+	}()
+
+	for {
+		c, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			whitespace = append(whitespace, c)
+		default:
+			return c, reader.UnreadByte()
+		}
+	}
+}
+
+// prepareRequestBody decodes a JSON request body once and applies the field name transform, read-only fields, time
+// format, and strict binding to it. The body is replaced with the result so it can be bound normally. The decoded
+// body is returned so defaults are only applied to omitted fields. It is nil if the body is not decoded
+func (a *API[T]) prepareRequestBody(r *http.Request) (any, error) {
+	target := a.instance()
+	_, defaultable := any(target).(Defaultable)
+	defaults := r.Method == http.MethodPost && defaultable
+	transforms := a.hasRequestTransforms(r, target)
+	if render.GetRequestContentType(r) != render.ContentTypeJSON || (!transforms && !defaults) {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := decodeJSONWithNumbers(bytes.NewReader(body))
+	if err != nil {
+		// Invalid bodies are left as-is so the error comes from binding
+		return nil, nil
+	}
+
+	if !transforms {
+		return data, nil
+	}
+
+	data = transformRequestFields(r, target, data)
+
+	err = removeReadOnlyFields(target, data, a.strictBinding)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = parseRequestTimes(r, target, data)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+
+	if a.strictBinding {
+		return data, checkUnknownFields(body, target)
+	}
+
+	return data, nil
+}
+
+func (a *API[T]) hasRequestTransforms(r *http.Request, target any) bool {
+	return a.strictBinding ||
+		hasFieldAccessTags(reflect.TypeOf(target)) ||
+		getFieldNameTransformFromContext(r.Context()) != nil ||
+		getTimeFormatFromContext(r.Context()) != ""
+}
+
+// checkUnknownFields decodes a JSON request body with DisallowUnknownFields to return an error if the body has
+// fields that don't exist in the target
+func checkUnknownFields(body []byte, target any) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(target)
 }
 
 // GetFromRequest will read a resource type from the request body or request context
@@ -192,8 +420,11 @@ func GetFromRequest[T RendererBinder](r *http.Request, instance func() T) (T, *E
 // GetRequestedResource reads the API's resource from storage based on the ID in the request URL
 func (a *API[T]) GetRequestedResource(r *http.Request) (T, *ErrResponse) {
 	id := a.GetIDParam(r)
+	if id == "" {
+		return *new(T), ErrInvalidRequest(ErrMissingID)
+	}
 
-	resource, err := a.Storage.Get(r.Context(), id)
+	resource, err := a.readResource(r, a.storageKey(r, id))
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return *new(T), ErrNotFoundResponse
@@ -202,11 +433,20 @@ func (a *API[T]) GetRequestedResource(r *http.Request) (T, *ErrResponse) {
 		return *new(T), InternalServerError(err)
 	}
 
+	if a.isGone(r, resource) {
+		return *new(T), ErrGoneResponse
+	}
+
 	return resource, nil
 }
 
+// Handler creates an http.HandlerFunc that renders the response returned by do. The status code can be set from
+// inside do with SetResponseStatus
 func Handler(do func(http.ResponseWriter, *http.Request) render.Renderer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		status := new(int)
+		r = r.WithContext(context.WithValue(r.Context(), responseStatusCtxKey, status))
+
 		response := do(w, r)
 
 		if response == nil {
@@ -217,6 +457,8 @@ func Handler(do func(http.ResponseWriter, *http.Request) render.Renderer) http.H
 		httpErr, ok := response.(*ErrResponse)
 		if ok {
 			logger.Error("error returned from handler", "error", httpErr.Err)
+		} else if *status != 0 {
+			render.Status(r, *status)
 		}
 
 		err := render.Render(w, r, response)
@@ -227,6 +469,37 @@ func Handler(do func(http.ResponseWriter, *http.Request) render.Renderer) http.H
 	}
 }
 
+// RenderHTML renders the provided template and data to a string. Rendering stops with an error if the context
+// is cancelled, which avoids wasting time on large templates when the client has already disconnected
+func RenderHTML(ctx context.Context, tmpl *template.Template, data any) (string, error) {
+	err := ctx.Err()
+	if err != nil {
+		return "", err
+	}
+
+	var renderedOutput bytes.Buffer
+	err = tmpl.Execute(&contextWriter{ctx, &renderedOutput}, data)
+	if err != nil {
+		return "", err
+	}
+
+	return renderedOutput.String(), nil
+}
+
+// contextWriter returns the context's error from Write when the context is done so template execution stops early
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (cw *contextWriter) Write(p []byte) (int, error) {
+	err := cw.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
+
 // MustRenderHTML renders the provided template and data to a string. Panics if there is an error
 func MustRenderHTML(tmpl *template.Template, data any) string {
 	var renderedOutput bytes.Buffer
@@ -0,0 +1,73 @@
+package babyapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// IDSetter is implemented by resources that can have their ID set by a generator from WithIDGenerator
+type IDSetter interface {
+	SetID(string) error
+}
+
+type idGenerator struct {
+	generate func() string
+	retries  int
+}
+
+// WithIDGenerator sets a function that creates IDs for new resources from POST requests. Since shorter ID schemes
+// can collide with existing resources, storage is checked for each generated ID and a new ID is generated up to
+// retries times before responding with an internal server error. The resource must implement IDSetter
+func (a *API[T]) WithIDGenerator(generate func() string, retries int) *API[T] {
+	a.panicIfReadOnly()
+
+	if a.instance == nil {
+		a.errors = append(a.errors, fmt.Errorf("WithIDGenerator: cannot be used with a root API"))
+		return a
+	}
+
+	if _, ok := any(a.instance()).(IDSetter); !ok {
+		a.errors = append(a.errors, fmt.Errorf("WithIDGenerator: resource type %T does not implement IDSetter", a.instance()))
+		return a
+	}
+
+	a.idGenerator = idGenerator{generate, retries}
+	return a
+}
+
+// generateID sets a new ID on the resource using the ID generator, if it is set. It returns an error if a unique ID
+// could not be generated
+func (a *API[T]) generateID(r *http.Request, resource T) *ErrResponse {
+	if a.idGenerator.generate == nil {
+		return nil
+	}
+
+	logger := GetLoggerFromContext(r.Context())
+
+	for attempt := 0; attempt <= a.idGenerator.retries; attempt++ {
+		id := a.idGenerator.generate()
+
+		_, err := a.getReadStorage().Get(r.Context(), a.storageKey(r, id))
+		if err == nil {
+			logger.Warn("generated ID already exists", "id", id, "attempt", attempt+1)
+			continue
+		}
+		if !errors.Is(err, ErrNotFound) {
+			logger.Error("error checking if generated ID exists", "error", err)
+			return InternalServerError(err)
+		}
+
+		err = any(resource).(IDSetter).SetID(id)
+		if err != nil {
+			logger.Error("error setting generated ID", "error", err)
+			return InternalServerError(err)
+		}
+
+		return nil
+	}
+
+	err := fmt.Errorf("unable to generate a unique ID after %d attempts", a.idGenerator.retries+1)
+	logger.Error("error generating ID", "error", err)
+	return InternalServerError(err)
+}
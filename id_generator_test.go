@@ -0,0 +1,105 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type ShortIDAlbum struct {
+	babyapi.DefaultRenderer
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+func (a *ShortIDAlbum) GetID() string {
+	return a.ID
+}
+
+func (a *ShortIDAlbum) SetID(id string) error {
+	a.ID = id
+	return nil
+}
+
+func (*ShortIDAlbum) Bind(*http.Request) error {
+	return nil
+}
+
+// sequenceGenerator returns the IDs in order
+func sequenceGenerator(ids ...string) func() string {
+	return func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+}
+
+func TestIDGenerator(t *testing.T) {
+	tests := []struct {
+		name           string
+		ids            []string
+		retries        int
+		expectedStatus int
+		expectedID     string
+	}{
+		{"NoCollision", []string{"b"}, 0, http.StatusCreated, "b"},
+		{"RetryCollision", []string{"a", "a", "b"}, 2, http.StatusCreated, "b"},
+		{"TooManyCollisions", []string{"a", "a"}, 1, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *ShortIDAlbum { return &ShortIDAlbum{} }).
+				WithIDGenerator(sequenceGenerator(tt.ids...), tt.retries)
+			require.NoError(t, api.Storage.Set(context.Background(), &ShortIDAlbum{ID: "a", Title: "Existing"}))
+
+			r, err := http.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"New"}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*ShortIDAlbum](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedID == "" {
+				return
+			}
+
+			require.JSONEq(t, `{"id":"`+tt.expectedID+`","title":"New"}`, w.Body.String())
+
+			existing, err := api.Storage.Get(context.Background(), "a")
+			require.NoError(t, err)
+			require.Equal(t, "Existing", existing.Title)
+		})
+	}
+
+	t.Run("MissingIDSetter", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithIDGenerator(sequenceGenerator("a"), 0)
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, "WithIDGenerator: resource type *babyapi_test.Album does not implement IDSetter")
+	})
+}
+
+func TestIDGeneratorImportKeepsIDs(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *ShortIDAlbum { return &ShortIDAlbum{} }).
+		WithImportExport().
+		WithIDGenerator(func() string { return "generated" }, 0)
+
+	r, err := http.NewRequest(http.MethodPost, "/albums/import", strings.NewReader(`{"id":"original","title":"Imported"}`))
+	require.NoError(t, err)
+
+	w := babytest.TestRequest[*ShortIDAlbum](t, api, r)
+	require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+
+	album, err := api.Storage.Get(context.Background(), "original")
+	require.NoError(t, err)
+	require.Equal(t, "Imported", album.Title)
+
+	_, err = api.Storage.Get(context.Background(), "generated")
+	require.ErrorIs(t, err, babyapi.ErrNotFound)
+}
@@ -0,0 +1,42 @@
+package babyapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+)
+
+// WithIDType sets a function that validates the ID from the URL before it is used to read from storage. Requests
+// with invalid IDs get a 400 Bad Request response with the ErrCodeInvalidID code. Use IntID for integer IDs
+func (a *API[T]) WithIDType(parse func(string) error) *API[T] {
+	a.panicIfReadOnly()
+
+	a.idType = parse
+	return a
+}
+
+// IntID can be used with WithIDType for resources with integer IDs
+func IntID(id string) error {
+	_, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("expected an integer")
+	}
+	return nil
+}
+
+func (a *API[T]) idTypeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := a.GetIDParam(r)
+
+		err := a.idType(id)
+		if err != nil {
+			GetLoggerFromContext(r.Context()).Warn("invalid ID", "id", id, "error", err)
+			_ = render.Render(w, r, ErrInvalidRequestCoded(ErrCodeInvalidID, fmt.Errorf("invalid ID %q: %w", id, err)))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,42 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDType(t *testing.T) {
+	api := babyapi.NewAPI("Albums", "/albums", func() *ShortIDAlbum { return &ShortIDAlbum{} }).
+		WithIDType(babyapi.IntID)
+	require.NoError(t, api.Storage.Set(context.Background(), &ShortIDAlbum{ID: "1", Title: "Album1"}))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"Valid", http.MethodGet, "/albums/1", http.StatusOK, `{"id":"1","title":"Album1"}`},
+		{"ValidNotFound", http.MethodGet, "/albums/2", http.StatusNotFound, `{"status":"Resource not found.","error_code":"not_found"}`},
+		{"Invalid", http.MethodGet, "/albums/abc", http.StatusBadRequest, `{"status":"Invalid request.","error_code":"invalid_id","error":"invalid ID \"abc\": expected an integer"}`},
+		{"InvalidDelete", http.MethodDelete, "/albums/1.5", http.StatusBadRequest, `{"status":"Invalid request.","error_code":"invalid_id","error":"invalid ID \"1.5\": expected an integer"}`},
+		{"CollectionNotValidated", http.MethodGet, "/albums", http.StatusOK, `{"items":[{"id":"1","title":"Album1"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.path, http.NoBody)
+			require.NoError(t, err)
+
+			w := babytest.TestRequest[*ShortIDAlbum](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			require.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
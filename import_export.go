@@ -0,0 +1,119 @@
+package babyapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// WithImportExport adds routes for backing up and restoring all resources as newline-delimited JSON:
+//   - GET /base/export streams resources from storage with the same filters as GetAll, including SetGetAllFilter.
+//     Write-only fields are not exported since the export is a response like any other, so they can't be restored
+//     from an export
+//   - POST /base/import reads resources one at a time and upserts them with the same binding and hooks as PUT. The
+//     response is a BulkResults with one BulkResult per item
+func (a *API[T]) WithImportExport() *API[T] {
+	a.panicIfReadOnly()
+
+	a.importExport = true
+	return a
+}
+
+func (a *API[T]) defaultExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := GetLoggerFromContext(r.Context())
+
+		filter, err := a.getAllFilterFunc(r)
+		if err != nil {
+			logger.Error("error parsing filters", "error", err)
+			_ = render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+
+		resources, err := a.getReadStorage().GetAll(r.Context(), r.URL.Query())
+		if err != nil {
+			logger.Error("error getting resources", "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+		resources = filter.Filter(resources)
+
+		logger.Info("exporting resources", "count", len(resources))
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		flusher, _ := w.(http.Flusher)
+		encoder := getJSONCodecFromContext(r.Context()).NewEncoder(w)
+		for _, resource := range resources {
+			if r.Context().Err() != nil {
+				logger.Warn("request cancelled while exporting resources", "error", r.Context().Err())
+				return
+			}
+
+			var data any = resource
+			if fieldAccessEnabled(r) {
+				data, err = toGenericJSON(resource)
+				if err != nil {
+					logger.Error("error removing write-only fields", "id", resource.GetID(), "error", err)
+					return
+				}
+				data = removeWriteOnlyFields(r, resource, data)
+			}
+
+			err = encoder.Encode(data)
+			if err != nil {
+				logger.Error("error exporting resource", "id", resource.GetID(), "error", err)
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func (a *API[T]) defaultImport() http.HandlerFunc {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
+		logger := GetLoggerFromContext(r.Context())
+
+		results := BulkResults[T]{}
+		decoder := json.NewDecoder(r.Body)
+		for i := 0; ; i++ {
+			var item json.RawMessage
+			err := decoder.Decode(&item)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				// The rest of the stream can't be read after invalid JSON, so stop here
+				httpErr := ErrInvalidRequest(fmt.Errorf("error decoding item: %w", err))
+				results = append(results, BulkResult[T]{Index: i, Status: httpErr.HTTPStatusCode, Error: httpErr})
+				break
+			}
+
+			result := BulkResult[T]{Index: i}
+
+			resource, httpErr := a.storeBulkItem(w, r, http.MethodPut, item)
+			if httpErr != nil {
+				logger.Error("error importing resource", "index", i, "error", httpErr)
+				result.Status = httpErr.HTTPStatusCode
+				result.Error = httpErr
+			} else {
+				result.Status = a.responseCodes[http.MethodPut]
+				result.Resource = resource
+			}
+
+			results = append(results, result)
+		}
+
+		logger.Info("imported resources", "count", len(results))
+
+		return results
+	})
+}
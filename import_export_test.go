@@ -0,0 +1,122 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportExport(t *testing.T) {
+	album1 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+	album2 := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album2"}
+
+	source := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithImportExport()
+	require.NoError(t, source.Storage.Set(context.Background(), album1))
+	require.NoError(t, source.Storage.Set(context.Background(), album2))
+
+	var exported string
+	t.Run("Export", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums/export", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, source, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "application/x-ndjson", w.Result().Header.Get("Content-Type"))
+
+		exported = w.Body.String()
+		lines := strings.Split(strings.TrimSpace(exported), "\n")
+		require.Len(t, lines, 2)
+
+		albums := []*Album{}
+		for _, line := range lines {
+			var album Album
+			require.NoError(t, json.Unmarshal([]byte(line), &album))
+			albums = append(albums, &album)
+		}
+		require.ElementsMatch(t, []*Album{album1, album2}, albums)
+	})
+
+	destination := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithImportExport()
+
+	importAlbums := func(t *testing.T, body string) []babyapi.BulkResult[*Album] {
+		r, err := http.NewRequest(http.MethodPost, "/albums/import", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/x-ndjson")
+
+		w := babytest.TestRequest[*Album](t, destination, r)
+		require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+
+		var results []babyapi.BulkResult[*Album]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		return results
+	}
+
+	t.Run("Import", func(t *testing.T) {
+		results := importAlbums(t, exported)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.Equal(t, http.StatusOK, result.Status)
+		}
+
+		stored, err := destination.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*Album{album1, album2}, stored)
+	})
+
+	t.Run("ImportIsUpsert", func(t *testing.T) {
+		results := importAlbums(t, `{"id":"`+album1.GetID()+`","title":"NewTitle"}`)
+		require.Len(t, results, 1)
+		require.Equal(t, http.StatusOK, results[0].Status)
+
+		stored, err := destination.Storage.Get(context.Background(), album1.GetID())
+		require.NoError(t, err)
+		require.Equal(t, "NewTitle", stored.Title)
+	})
+
+	t.Run("ImportErrors", func(t *testing.T) {
+		results := importAlbums(t, `{"title":"MissingID"}
+{"id":"`+album2.GetID()+`","title":"Album2"}
+{"id":`)
+		require.Len(t, results, 3)
+
+		require.Equal(t, http.StatusBadRequest, results[0].Status)
+		require.Equal(t, "missing required id field", results[0].Error.ErrorText)
+
+		require.Equal(t, http.StatusOK, results[1].Status)
+
+		require.Equal(t, 2, results[2].Index)
+		require.Equal(t, http.StatusBadRequest, results[2].Status)
+		require.Equal(t, "error decoding item: unexpected EOF", results[2].Error.ErrorText)
+	})
+}
+
+func TestExportFiltersAndMasks(t *testing.T) {
+	api := babyapi.NewAPI("Users", "/users", func() *User { return &User{} }).
+		WithImportExport().
+		SetGetAllFilter(func(r *http.Request) babyapi.FilterFunc[*User] {
+			name := r.Header.Get("X-User")
+			return func(u *User) bool { return u.Name == name }
+		})
+
+	user1 := &User{DefaultResource: babyapi.NewDefaultResource(), Name: "User1", Password: "secret1"}
+	user2 := &User{DefaultResource: babyapi.NewDefaultResource(), Name: "User2", Password: "secret2"}
+	require.NoError(t, api.Storage.Set(context.Background(), user1))
+	require.NoError(t, api.Storage.Set(context.Background(), user2))
+
+	r, err := http.NewRequest(http.MethodGet, "/users/export", http.NoBody)
+	require.NoError(t, err)
+	r.Header.Set("X-User", "User1")
+
+	w := babytest.TestRequest[*User](t, api, r)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.JSONEq(t, `{"id":"`+user1.GetID()+`","name":"User1"}`, w.Body.String())
+}
@@ -0,0 +1,85 @@
+package babyapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// JSONCodec is used to encode JSON responses and decode JSON request bodies. It allows using a different JSON library
+// instead of encoding/json. Implementations should escape HTML like encoding/json so existing clients are not
+// affected
+type JSONCodec interface {
+	Unmarshal(data []byte, v any) error
+	NewEncoder(w io.Writer) JSONEncoder
+}
+
+// JSONEncoder writes JSON values to a stream
+type JSONEncoder interface {
+	Encode(v any) error
+}
+
+// StdJSONCodec is the default JSONCodec and uses encoding/json
+type StdJSONCodec struct{}
+
+var _ JSONCodec = StdJSONCodec{}
+
+func (StdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (StdJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(true)
+	return enc
+}
+
+// WithJSONCodec sets the JSONCodec used to encode responses and decode request bodies. Other JSON handling still
+// uses encoding/json. This includes options that transform responses, like WithTimeFormat, WithFieldNameTransform,
+// WithFieldSelection, and write-only fields, since the response is marshalled with encoding/json before it is
+// changed and encoded with the codec. Nested APIs use the codec from their parent unless they set their own
+func (a *API[T]) WithJSONCodec(codec JSONCodec) *API[T] {
+	a.panicIfReadOnly()
+
+	a.jsonCodec = codec
+	return a
+}
+
+func (a *API[T]) jsonCodecMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), jsonCodecCtxKey, a.jsonCodec)))
+	})
+}
+
+// getJSONCodecFromContext returns the JSONCodec from the context or StdJSONCodec if there isn't one
+func getJSONCodecFromContext(ctx context.Context) JSONCodec {
+	codec, ok := ctx.Value(jsonCodecCtxKey).(JSONCodec)
+	if !ok {
+		return StdJSONCodec{}
+	}
+	return codec
+}
+
+// decodeRequest replaces render.Decode so JSON request bodies are decoded with the JSONCodec from the request context.
+// Decoding is skipped for empty bodies allowed by WithAllowEmptyBody
+func decodeRequest(r *http.Request, v any) error {
+	if empty, _ := r.Context().Value(emptyBodyCtxKey).(bool); empty {
+		return nil
+	}
+
+	codec, ok := r.Context().Value(jsonCodecCtxKey).(JSONCodec)
+	if !ok || render.GetRequestContentType(r) != render.ContentTypeJSON {
+		return render.DefaultDecoder(r, v)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("error reading request body: %w", err)
+	}
+
+	return codec.Unmarshal(body, v)
+}
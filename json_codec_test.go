@@ -0,0 +1,58 @@
+package babyapi_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCodec uses encoding/json and counts how many times it is used
+type countingCodec struct {
+	babyapi.StdJSONCodec
+	encodes   atomic.Int64
+	unmarshal atomic.Int64
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshal.Add(1)
+	return c.StdJSONCodec.Unmarshal(data, v)
+}
+
+func (c *countingCodec) NewEncoder(w io.Writer) babyapi.JSONEncoder {
+	c.encodes.Add(1)
+	return c.StdJSONCodec.NewEncoder(w)
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithJSONCodec(codec)
+
+	t.Run("DecodeRequest", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"<Album1>"}`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), `"title":"\u003cAlbum1\u003e"`)
+
+		require.Equal(t, int64(1), codec.unmarshal.Load())
+		require.Equal(t, int64(1), codec.encodes.Load())
+	})
+
+	t.Run("EncodeErrorResponse", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/albums/DoesNotExist", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+		require.Equal(t, int64(2), codec.encodes.Load())
+	})
+}
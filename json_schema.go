@@ -0,0 +1,142 @@
+package babyapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var idType = reflect.TypeOf(ID{})
+
+// WithSchemaEndpoint adds a GET /schema route to the API that responds with the resource's JSON Schema
+func (a *API[T]) WithSchemaEndpoint() *API[T] {
+	a.panicIfReadOnly()
+
+	a.schemaEndpoint = true
+	return a
+}
+
+// JSONSchema creates a JSON Schema document for the resource type using reflection. It uses the same JSON field
+// names as responses, including the transform from WithFieldNameTransform. Fields with the readonly and writeonly
+// babyapi tags use the readOnly and writeOnly keywords. Types with custom JSON encoding allow any value
+func (a *API[T]) JSONSchema() ([]byte, error) {
+	if a.instance == nil {
+		return nil, errors.New("API does not have a resource type")
+	}
+
+	schema := a.typeSchema(reflect.TypeOf(a.instance()), map[reflect.Type]bool{})
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = a.name
+
+	return json.Marshal(schema)
+}
+
+func (a *API[T]) defaultSchema() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := GetLoggerFromContext(r.Context())
+
+		schema, err := a.JSONSchema()
+		if err != nil {
+			logger.Error("error creating JSON Schema", "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/schema+json")
+		_, _ = w.Write(schema)
+	}
+}
+
+// typeSchema creates the schema for a Go type. Types that are already being visited are not expanded again so
+// recursive types don't loop forever
+func (a *API[T]) typeSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return a.timeSchema()
+	}
+
+	if t == idType {
+		return map[string]any{"type": "string"}
+	}
+
+	// encoding/json uses strings for types that only implement encoding.TextMarshaler
+	implements := func(iface reflect.Type) bool {
+		return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+	}
+	if implements(textMarshalerType) && !implements(jsonMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	if hasCustomJSON(t) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json uses base64 strings for byte slices
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": a.typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": a.typeSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]any{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := map[string]any{}
+		for _, field := range getJSONFields(t) {
+			name := field.name
+			if !field.tagged && a.fieldNameTransform != nil {
+				name = a.fieldNameTransform(name)
+			}
+
+			fieldSchema := a.typeSchema(field.typ, visiting)
+			switch field.access {
+			case FieldAccessReadOnly:
+				fieldSchema["readOnly"] = true
+			case FieldAccessWriteOnly:
+				fieldSchema["writeOnly"] = true
+			}
+
+			properties[name] = fieldSchema
+		}
+
+		return map[string]any{"type": "object", "properties": properties}
+	default:
+		return map[string]any{}
+	}
+}
+
+// timeSchema creates the schema for time.Time fields based on the format from WithTimeFormat
+func (a *API[T]) timeSchema() map[string]any {
+	switch a.timeFormat {
+	case "", time.RFC3339, time.RFC3339Nano:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case TimeFormatUnix, TimeFormatUnixMilli:
+		return map[string]any{"type": []string{"integer", "null"}}
+	default:
+		return map[string]any{"type": []string{"string", "null"}}
+	}
+}
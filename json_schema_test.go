@@ -0,0 +1,81 @@
+package babyapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type SchemaUser struct {
+	babyapi.DefaultResource
+	Name      string         `json:"name"`
+	Password  string         `json:"password" babyapi:"writeonly"`
+	CreatedAt time.Time      `json:"created_at" babyapi:"readonly"`
+	Tags      []string       `json:"tags,omitempty"`
+	Labels    map[string]int `json:"labels"`
+	Avatar    []byte         `json:"avatar"`
+	Manager   *SchemaUser    `json:"manager"`
+	Score     float64
+	Extra     json.RawMessage   `json:"extra"`
+	Ignored   string            `json:"-"`
+	Metadata  map[string]string `json:"-"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	api := babyapi.NewAPI("Users", "/users", func() *SchemaUser { return &SchemaUser{} }).
+		WithFieldNameTransform(toSnakeCase).
+		WithSchemaEndpoint()
+
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Users",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"name": {"type": "string"},
+			"password": {"type": "string", "writeOnly": true},
+			"created_at": {"type": "string", "format": "date-time", "readOnly": true},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"avatar": {"type": "string", "contentEncoding": "base64"},
+			"manager": {"type": "object"},
+			"score": {"type": "number"},
+			"extra": {}
+		}
+	}`
+
+	t.Run("JSONSchema", func(t *testing.T) {
+		schema, err := api.JSONSchema()
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(schema))
+	})
+
+	t.Run("SchemaEndpoint", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/users/schema", http.NoBody)
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*SchemaUser](t, api, r)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
+	t.Run("UnixTimeFormat", func(t *testing.T) {
+		api := babyapi.NewAPI("Users", "/users", func() *SchemaUser { return &SchemaUser{} }).
+			WithTimeFormat(babyapi.TimeFormatUnix)
+
+		schema, err := api.JSONSchema()
+		require.NoError(t, err)
+
+		var result struct {
+			Properties map[string]map[string]any `json:"properties"`
+		}
+		require.NoError(t, json.Unmarshal(schema, &result))
+		require.Equal(t, []any{"integer", "null"}, result.Properties["created_at"]["type"])
+	})
+}
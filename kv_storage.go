@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/madflojo/hord"
@@ -21,11 +23,23 @@ import (
 type KVStorage[T Resource] struct {
 	prefix string
 	db     hord.Database
+
+	// version is changed on every write so it can be used for collection ETags. It starts with the current time so
+	// versions from before a restart are not reused
+	version atomic.Int64
 }
 
 // NewKVStorage creates a new storage client for the specified type. It stores resources with keys prefixed by 'prefix'
 func NewKVStorage[T Resource](db hord.Database, prefix string) Storage[T] {
-	return &KVStorage[T]{prefix, db}
+	storage := &KVStorage[T]{prefix: prefix, db: db}
+	storage.version.Store(time.Now().UnixNano())
+	return storage
+}
+
+// Version implements VersionedStorage. The version is only tracked in memory, so it will not change when
+// a shared database is modified by another process
+func (c *KVStorage[T]) Version(context.Context) (string, error) {
+	return strconv.FormatInt(c.version.Load(), 10), nil
 }
 
 func (c *KVStorage[T]) key(id string) string {
@@ -43,12 +57,14 @@ func (c *KVStorage[T]) Delete(ctx context.Context, id string) error {
 	}
 
 	endDateable, ok := any(result).(EndDateable)
-	if !ok {
-		return c.db.Delete(key)
-	}
+	if !ok || endDateable.EndDated() {
+		err = c.db.Delete(key)
+		if err != nil {
+			return err
+		}
 
-	if endDateable.EndDated() {
-		return c.db.Delete(key)
+		c.version.Add(1)
+		return nil
 	}
 
 	endDateable.SetEndDate(time.Now())
@@ -115,17 +131,18 @@ func (c *KVStorage[T]) GetAll(_ context.Context, query url.Values) ([]T, error)
 	return results, nil
 }
 
-// Set marshals the provided item and writes it to the database
+// Set marshals the provided item and writes it to the database. The key is created using StorageKey
 func (c *KVStorage[T]) Set(_ context.Context, item T) error {
 	asBytes, err := json.Marshal(item)
 	if err != nil {
 		return fmt.Errorf("error marshalling data: %w", err)
 	}
 
-	err = c.db.Set(c.key(item.GetID()), asBytes)
+	err = c.db.Set(c.key(StorageKey(item)), asBytes)
 	if err != nil {
 		return fmt.Errorf("error writing data to database: %w", err)
 	}
 
+	c.version.Add(1)
 	return nil
 }
@@ -0,0 +1,121 @@
+package babyapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/render"
+)
+
+// Locker is used to lock a resource while it is read, modified, and written by PUT and PATCH requests so concurrent
+// updates are not lost. Implementations can use a distributed lock, like Redis, when running multiple instances
+type Locker interface {
+	Lock(ctx context.Context, key string) error
+	Unlock(ctx context.Context, key string) error
+}
+
+// WithResourceLocker sets a Locker that is used to lock each resource for the duration of PUT and PATCH requests.
+// Use NewKeyedMutex for an in-memory Locker
+func (a *API[T]) WithResourceLocker(locker Locker) *API[T] {
+	a.panicIfReadOnly()
+
+	a.locker = locker
+	return a
+}
+
+// resourceLockMiddleware locks the requested resource for PUT and PATCH requests. It must run before the resource is
+// read so the whole read-modify-write is protected
+func (a *API[T]) resourceLockMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut && r.Method != http.MethodPatch {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := GetLoggerFromContext(r.Context())
+		key := fmt.Sprintf("%s/%s", a.name, a.storageKey(r, a.GetIDParam(r)))
+
+		err := a.locker.Lock(r.Context(), key)
+		if err != nil {
+			logger.Error("error locking resource", "key", key, "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+
+		defer func() {
+			// Use a new context since the request's context might be cancelled
+			err := a.locker.Unlock(context.Background(), key)
+			if err != nil {
+				logger.Error("error unlocking resource", "key", key, "error", err)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// KeyedMutex is an in-memory Locker with a separate lock for each key
+type KeyedMutex struct {
+	lock  sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	c    chan struct{}
+	refs int
+}
+
+var _ Locker = &KeyedMutex{}
+
+// NewKeyedMutex creates a new in-memory Locker
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{locks: map[string]*keyedLock{}}
+}
+
+// Lock waits until the key is unlocked or the context is done
+func (m *KeyedMutex) Lock(ctx context.Context, key string) error {
+	m.lock.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{c: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.lock.Unlock()
+
+	select {
+	case l.c <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		m.lock.Lock()
+		m.release(key, l)
+		m.lock.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Unlock unlocks the key. It returns an error if the key is not locked
+func (m *KeyedMutex) Unlock(_ context.Context, key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	l, ok := m.locks[key]
+	if !ok || len(l.c) == 0 {
+		return fmt.Errorf("key %q is not locked", key)
+	}
+
+	<-l.c
+	m.release(key, l)
+
+	return nil
+}
+
+// release removes a reference to the lock and deletes it when it is no longer used. m.lock must be held
+func (m *KeyedMutex) release(key string, l *keyedLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
@@ -0,0 +1,87 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+type Counter struct {
+	babyapi.DefaultResource
+	Count int `json:"count"`
+}
+
+func (c *Counter) Patch(newCounter *Counter) *babyapi.ErrResponse {
+	// Sleep between the read and write to make lost updates likely without locking
+	time.Sleep(time.Millisecond)
+	c.Count += newCounter.Count
+	return nil
+}
+
+func TestResourceLocker(t *testing.T) {
+	api := babyapi.NewAPI("Counters", "/counters", func() *Counter { return &Counter{} }).
+		WithResourceLocker(babyapi.NewKeyedMutex())
+
+	counter := &Counter{DefaultResource: babyapi.NewDefaultResource()}
+	require.NoError(t, api.Storage.Set(context.Background(), counter))
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r := httptest.NewRequest(http.MethodPatch, "/counters/"+counter.GetID(), strings.NewReader(`{"count":1}`))
+			r.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			statuses <- w.Result().StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		require.Equal(t, http.StatusOK, status)
+	}
+
+	result, err := api.Storage.Get(context.Background(), counter.GetID())
+	require.NoError(t, err)
+	require.Equal(t, 20, result.Count)
+}
+
+func TestKeyedMutex(t *testing.T) {
+	m := babyapi.NewKeyedMutex()
+
+	require.NoError(t, m.Lock(context.Background(), "key1"))
+	require.NoError(t, m.Lock(context.Background(), "key2"))
+
+	t.Run("LockedKeyWaitsForContext", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, m.Lock(ctx, "key1"), context.DeadlineExceeded)
+	})
+
+	t.Run("UnlockAllowsLock", func(t *testing.T) {
+		require.NoError(t, m.Unlock(context.Background(), "key1"))
+		require.NoError(t, m.Lock(context.Background(), "key1"))
+		require.NoError(t, m.Unlock(context.Background(), "key1"))
+	})
+
+	t.Run("UnlockNotLocked", func(t *testing.T) {
+		require.EqualError(t, m.Unlock(context.Background(), "key3"), `key "key3" is not locked`)
+	})
+}
@@ -0,0 +1,51 @@
+package babyapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// SetMaintenanceMode turns maintenance mode on or off. While it is on, requests that modify resources are rejected
+// with 503 Service Unavailable and a Retry-After header so writes can be drained before a migration. Reads are still
+// allowed unless WithMaintenanceBlockReads is used. This applies to the API and its nested APIs and is safe to call
+// while the server is running
+func (a *API[T]) SetMaintenanceMode(on bool) {
+	a.maintenance.Store(on)
+}
+
+// InMaintenanceMode returns true if maintenance mode is on for the API
+func (a *API[T]) InMaintenanceMode() bool {
+	return a.maintenance.Load()
+}
+
+// WithMaintenanceBlockReads will also reject read requests while the API is in maintenance mode
+func (a *API[T]) WithMaintenanceBlockReads() *API[T] {
+	a.panicIfReadOnly()
+
+	a.maintenanceBlockReads = true
+	return a
+}
+
+// maintenanceMiddleware rejects requests when maintenance mode is on. Paths that are exempt from WithMaxInFlight,
+// like /health, are always allowed
+func (a *API[T]) maintenanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.maintenance.Load() || maxInFlightExemptPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if !a.maintenanceBlockReads {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		GetLoggerFromContext(r.Context()).Warn("rejecting request because the API is in maintenance mode", "method", r.Method)
+		SetRetryAfter(w, a.getRetryAfter())
+		_ = render.Render(w, r, ErrMaintenanceResponse)
+	})
+}
@@ -0,0 +1,81 @@
+package babyapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		blockReads bool
+		method     string
+		path       string
+		body       string
+		expected   int
+	}{
+		{"WriteRejected", false, http.MethodPost, "/albums", `{"title":"New Album"}`, http.StatusServiceUnavailable},
+		{"DeleteRejected", false, http.MethodDelete, "/albums/cljcqg5o402e9s28rbp0", "", http.StatusServiceUnavailable},
+		{"ReadAllowed", false, http.MethodGet, "/albums", "", http.StatusOK},
+		{"ReadRejected", true, http.MethodGet, "/albums", "", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+			if tt.blockReads {
+				api.WithMaintenanceBlockReads()
+			}
+
+			router, err := api.Router()
+			require.NoError(t, err)
+
+			api.SetMaintenanceMode(true)
+			require.True(t, api.InMaintenanceMode())
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			require.Equal(t, tt.expected, w.Result().StatusCode)
+
+			if tt.expected == http.StatusServiceUnavailable {
+				require.Equal(t, "1", w.Result().Header.Get("Retry-After"))
+				require.Equal(t, `{"status":"Service unavailable for maintenance.","error_code":"maintenance"}`, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+
+	t.Run("ToggledOff", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		api.SetMaintenanceMode(true)
+		api.SetMaintenanceMode(false)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"New Album"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+	})
+
+	t.Run("NestedAPI", func(t *testing.T) {
+		artistAPI := babyapi.NewAPI("Artists", "/artists", func() *Artist { return &Artist{} })
+		albumAPI := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })
+		artistAPI.AddNestedAPI(albumAPI)
+
+		router, err := artistAPI.Router()
+		require.NoError(t, err)
+
+		artistAPI.SetMaintenanceMode(true)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/artists/cljcqg5o402e9s28rbp0/albums", strings.NewReader(`{"title":"New Album"}`)))
+		require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+	})
+}
@@ -1,6 +1,8 @@
 package babyapi
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -15,6 +17,80 @@ func (a *API[T]) DefaultMiddleware(r chi.Router) {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 	r.Use(a.logMiddleware)
+	r.Use(a.shutdownMiddleware)
+
+	if a.maxInFlight > 0 {
+		r.Use(maxInFlightMiddleware(a.maxInFlight, a.getRetryAfter()))
+	}
+}
+
+func devModeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), devModeCtxKey, true)))
+	})
+}
+
+func isDevMode(ctx context.Context) bool {
+	devMode, _ := ctx.Value(devModeCtxKey).(bool)
+	return devMode
+}
+
+// maxInFlightExemptPaths are not limited by WithMaxInFlight so they remain available when the server is overloaded
+var maxInFlightExemptPaths = map[string]bool{
+	"/health":  true,
+	"/metrics": true,
+}
+
+// maxInFlightMiddleware uses a buffered channel as a semaphore to limit the number of concurrent requests. Requests
+// that exceed the limit are rejected with 503 Service Unavailable instead of waiting
+func maxInFlightMiddleware(limit int, retryAfter time.Duration) func(http.Handler) http.Handler {
+	semaphore := make(chan struct{}, limit)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxInFlightExemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+				next.ServeHTTP(w, r)
+			default:
+				GetLoggerFromContext(r.Context()).Warn("rejecting request because max in-flight requests limit is reached", "limit", limit)
+				SetRetryAfter(w, retryAfter)
+				_ = render.Render(w, r, ErrServiceUnavailableResponse)
+			}
+		})
+	}
+}
+
+// methodTimeoutMiddleware sets a timeout on the request context using the request's method. GET and HEAD requests use
+// getMethod so collection routes can use the MethodGetAll timeout
+func (a *API[T]) methodTimeoutMiddleware(getMethod string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method := r.Method
+			if method == http.MethodGet || method == http.MethodHead {
+				method = getMethod
+			}
+
+			timeout, ok := a.methodTimeouts[method]
+			if !ok && method == MethodGetAll {
+				timeout, ok = a.methodTimeouts[http.MethodGet]
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func (a *API[T]) logMiddleware(next http.Handler) http.Handler {
@@ -30,19 +106,36 @@ func (a *API[T]) logMiddleware(next http.Handler) http.Handler {
 
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
+		received := a.now()
 		t1 := time.Now()
+		completed := false
 		defer func() {
 			if r.URL.Path == "/metrics" {
 				return
 			}
+
+			// When the handler panics before writing a response, Recoverer responds with 500 after this runs
+			status := ww.Status()
+			if !completed && status == 0 {
+				status = http.StatusInternalServerError
+			}
+
+			if a.accessLog != nil {
+				err := a.accessLog.write(r, received, status, ww.BytesWritten(), time.Since(t1))
+				if err != nil {
+					logger.Error("error writing access log", "error", err)
+				}
+			}
+
 			logger.With(
-				"status", ww.Status(),
+				"status", status,
 				"bytes_written", ww.BytesWritten(),
 				"time_elapsed", time.Since(t1),
 			).Info("response completed")
 		}()
 
 		next.ServeHTTP(ww, r.WithContext(NewContextWithLogger(r.Context(), logger)))
+		completed = true
 	})
 }
 
@@ -63,10 +156,13 @@ func (a *API[T]) requestBodyMiddleware(next http.Handler) http.Handler {
 
 func (a *API[T]) resourceExistsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resource, httpErr := a.GetRequestedResource(r)
+		resource, httpErr := a.getRequestedResourceFields(r)
 		if httpErr != nil {
-			// Skip for PUT because it can be used to create new resources
-			if r.Method == http.MethodPut {
+			// Skip for PUT because it can be used to create new resources, but an ID is still required
+			if r.Method == http.MethodPut && !errors.Is(httpErr.Err, ErrMissingID) {
+				if httpErr == ErrNotFoundResponse {
+					r = r.WithContext(a.newContextWithResourceNotFound(r.Context()))
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
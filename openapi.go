@@ -0,0 +1,258 @@
+package babyapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/render"
+	"gopkg.in/yaml.v3"
+)
+
+const openAPISpecVersion = "3.1.0"
+
+// WithOpenAPIEndpoint adds routes that respond with an OpenAPI document for the API and its nested APIs. The document
+// is served as YAML from /openapi.yaml, as JSON from /openapi.json, and from /openapi using the Accept header to
+// choose YAML or JSON. The version is used for the document's info.version. This only applies to the root-level API
+func (a *API[T]) WithOpenAPIEndpoint(version string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.openAPIVersion = version
+	return a
+}
+
+// OpenAPIJSON creates an OpenAPI document in JSON for the API and its nested APIs. Resource schemas are created the
+// same way as JSONSchema
+func (a *API[T]) OpenAPIJSON() ([]byte, error) {
+	return json.Marshal(a.openAPIDocument())
+}
+
+// OpenAPIYAML creates an OpenAPI document in YAML for the API and its nested APIs
+func (a *API[T]) OpenAPIYAML() ([]byte, error) {
+	// Convert to generic JSON first so YAML uses the same field names and values as JSON
+	data, err := a.OpenAPIJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(generic)
+}
+
+func (a *API[T]) openAPIDocument() map[string]any {
+	version := a.openAPIVersion
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	paths := map[string]any{}
+	schemas := map[string]any{}
+	a.addOpenAPIPaths("", paths, schemas)
+
+	return map[string]any{
+		"openapi": openAPISpecVersion,
+		"info":    map[string]any{"title": a.name, "version": version},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+}
+
+// addOpenAPIPaths adds the operations for the API's default routes and its nested APIs. The prefix is the path of the
+// parent resource
+func (a *API[T]) addOpenAPIPaths(prefix string, paths, schemas map[string]any) {
+	base := prefix + a.base
+	if a.rootAPI || a.instance == nil {
+		for _, child := range a.subAPIs {
+			child.addOpenAPIPaths(strings.TrimSuffix(base, "/"), paths, schemas)
+		}
+		return
+	}
+
+	schemas[a.name] = a.typeSchema(reflect.TypeOf(a.instance()), map[reflect.Type]bool{})
+	ref := map[string]any{"$ref": "#/components/schemas/" + a.name}
+
+	// Responses include computed fields, so they use a schema that extends the resource
+	responseRef := ref
+	if len(a.computedFieldNames) > 0 {
+		computed := map[string]any{}
+		for _, name := range a.computedFieldNames {
+			computed[name] = map[string]any{"readOnly": true}
+		}
+
+		schemas[a.name+"Response"] = map[string]any{
+			"allOf": []any{ref, map[string]any{"type": "object", "properties": computed}},
+		}
+		responseRef = map[string]any{"$ref": "#/components/schemas/" + a.name + "Response"}
+	}
+
+	values := a.openAPIExamples()
+	examples := map[string]any{}
+	for i, value := range values {
+		examples[fmt.Sprintf("example%d", i)] = map[string]any{"value": value}
+	}
+	request := openAPIMediaType(ref, examples)
+	response := openAPIMediaType(responseRef, examples)
+
+	collection := map[string]any{}
+	if a.GetAll != nil {
+		list := openAPIMediaType(map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"items": map[string]any{"type": "array", "items": responseRef}},
+		}, nil)
+		if len(values) > 0 {
+			list["example"] = map[string]any{"items": values}
+		}
+
+		collection["get"] = a.openAPIOperation(base, "List "+a.name, MethodGetAll, nil, list)
+	}
+	if a.Post != nil {
+		collection["post"] = a.openAPIOperation(base, "Create "+a.name, http.MethodPost, request, response)
+	}
+	if len(collection) > 0 {
+		paths[base] = collection
+	}
+
+	idPath := fmt.Sprintf("%s/{%s}", base, a.IDParamKey())
+	resource := map[string]any{}
+	if a.Get != nil {
+		resource["get"] = a.openAPIOperation(idPath, "Get "+a.name, http.MethodGet, nil, response)
+	}
+	if a.Put != nil {
+		resource["put"] = a.openAPIOperation(idPath, "Create or replace "+a.name, http.MethodPut, request, response)
+	}
+	if a.Patch != nil && isPatcher[T]() {
+		resource["patch"] = a.openAPIOperation(idPath, "Update "+a.name, http.MethodPatch, request, response)
+	}
+	if a.Delete != nil {
+		resource["delete"] = a.openAPIOperation(idPath, "Delete "+a.name, http.MethodDelete, nil, nil)
+	}
+	if len(resource) > 0 {
+		paths[idPath] = resource
+	}
+
+	for _, child := range a.subAPIs {
+		child.addOpenAPIPaths(idPath, paths, schemas)
+	}
+}
+
+// openAPIExamples converts the API's examples to generic JSON with the same format as responses. Examples that can't
+// be converted are skipped since they are already reported when the routes are created
+func (a *API[T]) openAPIExamples() []any {
+	values := []any{}
+	for _, example := range a.Examples() {
+		value, err := a.exampleJSON(example)
+		if err != nil {
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// openAPIMediaType creates a media type object with the schema and optional examples
+func openAPIMediaType(schema any, examples map[string]any) map[string]any {
+	mediaType := map[string]any{"schema": schema}
+	if len(examples) > 0 {
+		mediaType["examples"] = examples
+	}
+
+	return mediaType
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}]+)\}`)
+
+// openAPIOperation creates an operation with the path parameters from the path. The request and response media types
+// are optional
+func (a *API[T]) openAPIOperation(path, summary, method string, request, response map[string]any) map[string]any {
+	parameters := []any{}
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	responseObject := map[string]any{"description": http.StatusText(a.responseCodes[method])}
+	if response != nil {
+		responseObject["content"] = map[string]any{"application/json": response}
+	}
+
+	operation := map[string]any{
+		"summary":   summary,
+		"responses": map[string]any{fmt.Sprint(a.responseCodes[method]): responseObject},
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if request != nil {
+		operation["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": request},
+		}
+	}
+
+	return operation
+}
+
+// isPatcher checks if the resource type implements Patcher so PATCH is only documented when it can be used
+func isPatcher[T Resource]() bool {
+	_, ok := any(*new(T)).(Patcher[T])
+	return ok
+}
+
+// acceptsYAML checks if the request's Accept header prefers YAML
+func acceptsYAML(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		switch mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			return true
+		case "application/json":
+			return false
+		}
+	}
+
+	return false
+}
+
+// defaultOpenAPI responds with the OpenAPI document in the format, which is "json" or "yaml". If the format is empty,
+// it is chosen from the Accept header
+func (a *API[T]) defaultOpenAPI(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := GetLoggerFromContext(r.Context())
+
+		useYAML := format == "yaml" || (format == "" && acceptsYAML(r))
+
+		var data []byte
+		var err error
+		contentType := "application/json"
+		if useYAML {
+			data, err = a.OpenAPIYAML()
+			contentType = "application/yaml"
+		} else {
+			data, err = a.OpenAPIJSON()
+		}
+		if err != nil {
+			logger.Error("error creating OpenAPI document", "error", err)
+			_ = render.Render(w, r, InternalServerError(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(data)
+	}
+}
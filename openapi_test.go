@@ -0,0 +1,133 @@
+package babyapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOpenAPI(t *testing.T) {
+	api := babyapi.NewAPI("Artists", "/artists", func() *Artist { return &Artist{} }).
+		AddNestedAPI(babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} })).
+		WithOpenAPIEndpoint("1.2.3")
+
+	var expected map[string]any
+	data, err := api.OpenAPIJSON()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &expected))
+
+	require.Equal(t, "3.1.0", expected["openapi"])
+	require.Equal(t, map[string]any{"title": "Artists", "version": "1.2.3"}, expected["info"])
+
+	paths := expected["paths"].(map[string]any)
+	require.ElementsMatch(t, []string{
+		"/artists",
+		"/artists/{ArtistsID}",
+		"/artists/{ArtistsID}/albums",
+		"/artists/{ArtistsID}/albums/{AlbumsID}",
+	}, keys(paths))
+
+	// Artist doesn't implement Patcher
+	require.NotContains(t, paths["/artists/{ArtistsID}"], "patch")
+	require.Contains(t, paths["/artists/{ArtistsID}/albums/{AlbumsID}"], "patch")
+
+	albumParams := paths["/artists/{ArtistsID}/albums/{AlbumsID}"].(map[string]any)["get"].(map[string]any)["parameters"]
+	require.Len(t, albumParams, 2)
+
+	schemas := expected["components"].(map[string]any)["schemas"].(map[string]any)
+	require.ElementsMatch(t, []string{"Artists", "Albums"}, keys(schemas))
+
+	tests := []struct {
+		name                string
+		path                string
+		accept              string
+		expectedContentType string
+	}{
+		{"DefaultJSON", "/openapi", "", "application/json"},
+		{"AcceptYAML", "/openapi", "application/yaml", "application/yaml"},
+		{"AcceptJSON", "/openapi", "application/json, application/yaml", "application/json"},
+		{"ExplicitJSON", "/openapi.json", "application/yaml", "application/json"},
+		{"ExplicitYAML", "/openapi.yaml", "", "application/yaml"},
+	}
+
+	t.Run("ComputedFields", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithComputedFields(func(context.Context, *Album) map[string]any { return nil }, "rating")
+
+		data, err := api.OpenAPIJSON()
+		require.NoError(t, err)
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(data, &doc))
+
+		schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+		require.Equal(t, map[string]any{
+			"allOf": []any{
+				map[string]any{"$ref": "#/components/schemas/Albums"},
+				map[string]any{"type": "object", "properties": map[string]any{"rating": map[string]any{"readOnly": true}}},
+			},
+		}, schemas["AlbumsResponse"])
+
+		get := doc["paths"].(map[string]any)["/albums/{AlbumsID}"].(map[string]any)["get"].(map[string]any)
+		schema := get["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+		require.Equal(t, map[string]any{"$ref": "#/components/schemas/AlbumsResponse"}, schema)
+	})
+
+	t.Run("Examples", func(t *testing.T) {
+		album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Example"}
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).WithExamples(album)
+
+		data, err := api.OpenAPIJSON()
+		require.NoError(t, err)
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(data, &doc))
+
+		example := map[string]any{"id": album.GetID(), "title": "Example"}
+		collection := doc["paths"].(map[string]any)["/albums"].(map[string]any)
+
+		post := collection["post"].(map[string]any)
+		request := post["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+		require.Equal(t, map[string]any{"example0": map[string]any{"value": example}}, request["examples"])
+
+		response := post["responses"].(map[string]any)["201"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+		require.Equal(t, map[string]any{"example0": map[string]any{"value": example}}, response["examples"])
+
+		list := collection["get"].(map[string]any)["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+		require.Equal(t, map[string]any{"items": []any{example}}, list["example"])
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			require.NoError(t, err)
+			r.Header.Set("Accept", tt.accept)
+
+			w := babytest.TestRequest[*Artist](t, api, r)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+			require.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+
+			var doc map[string]any
+			if tt.expectedContentType == "application/yaml" {
+				require.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &doc))
+			} else {
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+			}
+			require.Equal(t, expected, doc)
+		})
+	}
+}
+
+func keys(m map[string]any) []string {
+	result := []string{}
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package babyapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// PostWriteHookPolicy controls what happens when the hook set by SetAfterCreateOrUpdate returns an error after the
+// resource is already stored
+type PostWriteHookPolicy int
+
+const (
+	// PostWriteHookFail responds with the hook's error and keeps the stored resource. This is the default
+	PostWriteHookFail PostWriteHookPolicy = iota
+	// PostWriteHookRollback undoes the write and responds with the hook's error. New resources are deleted and
+	// updated resources are restored to their previous state
+	PostWriteHookRollback
+	// PostWriteHookWarn keeps the stored resource and responds with success. The hook's error is logged and added to
+	// the response's Warning header
+	PostWriteHookWarn
+)
+
+// WithPostWriteHookPolicy sets how errors from the SetAfterCreateOrUpdate hook are handled by the default POST, PUT,
+// and PATCH handlers. The hook runs after storage is changed, so its error would otherwise leave the change in place
+func (a *API[T]) WithPostWriteHookPolicy(policy PostWriteHookPolicy) *API[T] {
+	a.panicIfReadOnly()
+
+	a.postWriteHookPolicy = policy
+	return a
+}
+
+// afterWrite runs the afterCreateOrUpdate hook after a resource is stored and handles errors using the policy
+func (a *API[T]) afterWrite(w http.ResponseWriter, r *http.Request, resource T) *ErrResponse {
+	httpErr := a.afterCreateOrUpdate(w, r, resource)
+	if httpErr == nil {
+		return nil
+	}
+
+	logger := GetLoggerFromContext(r.Context())
+
+	switch a.postWriteHookPolicy {
+	case PostWriteHookWarn:
+		logger.Warn("ignoring error from hook after storing resource", "error", httpErr.Err)
+
+		// Use the same text that the client would get in the error response
+		text := httpErr.StatusText
+		if httpErr.ErrorText != "" {
+			text = httpErr.ErrorText
+		}
+		w.Header().Add("Warning", fmt.Sprintf("199 - %q", text))
+		return nil
+	case PostWriteHookRollback:
+		err := a.rollbackWrite(r, resource)
+		if err != nil {
+			logger.Error("error rolling back resource after hook error", "error", err)
+			return InternalServerError(errors.Join(httpErr, fmt.Errorf("error rolling back: %w", err)))
+		}
+		logger.Info("rolled back resource after hook error", "error", httpErr.Err)
+	}
+
+	return httpErr
+}
+
+// recordPreviousResource reads the resource from storage before it is created so a rollback can restore it and change
+// events know if it was an update. This is needed for imports and bulk creates, which don't use
+// resourceExistsMiddleware. The returned request has the previous resource in its context or records that the resource
+// did not exist. Nothing is read unless the API uses PostWriteHookRollback or has change event listeners
+func (a *API[T]) recordPreviousResource(r *http.Request, id string) (*http.Request, *ErrResponse) {
+	if a.postWriteHookPolicy != PostWriteHookRollback && len(a.changeEventListeners) == 0 {
+		return r, nil
+	}
+
+	if _, err := a.GetResourceFromContext(r.Context()); err == nil || a.resourceNotFoundInContext(r.Context()) {
+		return r, nil
+	}
+
+	previous, err := a.getReadStorage().Get(r.Context(), a.storageKey(r, id))
+	switch {
+	case err == nil:
+		return r.WithContext(a.newContextWithResource(r.Context(), previous)), nil
+	case errors.Is(err, ErrNotFound):
+		return r.WithContext(a.newContextWithResourceNotFound(r.Context())), nil
+	default:
+		GetLoggerFromContext(r.Context()).Error("error reading resource before storing", "error", err)
+		return r, InternalServerError(err)
+	}
+}
+
+// rollbackWrite restores the previous resource from the request context or deletes the resource if it did not exist
+// before the request. It fails if the previous state is unknown so existing resources are never deleted
+func (a *API[T]) rollbackWrite(r *http.Request, resource T) error {
+	previous, err := a.GetResourceFromContext(r.Context())
+	if err == nil {
+		return a.getWriteStorage().Set(r.Context(), previous)
+	}
+
+	if !a.resourceNotFoundInContext(r.Context()) {
+		return fmt.Errorf("previous state of resource %q is unknown", resource.GetID())
+	}
+
+	err = a.getWriteStorage().Delete(r.Context(), a.storageKey(r, resource.GetID()))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package babyapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostWriteHookPolicy(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	tests := []struct {
+		name           string
+		policy         babyapi.PostWriteHookPolicy
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedTitles []string
+		expectWarning  bool
+	}{
+		{"PostFail", babyapi.PostWriteHookFail, http.MethodPost, "/albums", `{"title":"New"}`, http.StatusBadRequest, []string{"Album1", "New"}, false},
+		{"PostRollback", babyapi.PostWriteHookRollback, http.MethodPost, "/albums", `{"title":"New"}`, http.StatusBadRequest, []string{"Album1"}, false},
+		{"PostWarn", babyapi.PostWriteHookWarn, http.MethodPost, "/albums", `{"title":"New"}`, http.StatusCreated, []string{"Album1", "New"}, true},
+		{"PutNewRollback", babyapi.PostWriteHookRollback, http.MethodPut, "/albums/cqsbrb9jkrnn3bhbaf5g", `{"id":"cqsbrb9jkrnn3bhbaf5g","title":"New"}`, http.StatusBadRequest, []string{"Album1"}, false},
+		{"PutFail", babyapi.PostWriteHookFail, http.MethodPut, "/albums/" + album.GetID(), `{"id":"` + album.GetID() + `","title":"New"}`, http.StatusBadRequest, []string{"New"}, false},
+		{"PutRollback", babyapi.PostWriteHookRollback, http.MethodPut, "/albums/" + album.GetID(), `{"id":"` + album.GetID() + `","title":"New"}`, http.StatusBadRequest, []string{"Album1"}, false},
+		{"PatchRollback", babyapi.PostWriteHookRollback, http.MethodPatch, "/albums/" + album.GetID(), `{"title":"New"}`, http.StatusBadRequest, []string{"Album1"}, false},
+		{"PatchWarn", babyapi.PostWriteHookWarn, http.MethodPatch, "/albums/" + album.GetID(), `{"title":"New"}`, http.StatusOK, []string{"New"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				SetAfterCreateOrUpdate(func(http.ResponseWriter, *http.Request, *Album) *babyapi.ErrResponse {
+					return babyapi.ErrInvalidRequest(errors.New("hook error"))
+				}).
+				WithPostWriteHookPolicy(tt.policy)
+			require.NoError(t, api.Storage.Set(context.Background(), &Album{DefaultResource: album.DefaultResource, Title: album.Title}))
+
+			r, err := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+
+			if tt.expectWarning {
+				require.Equal(t, `199 - "hook error"`, w.Header().Get("Warning"))
+			} else {
+				require.Empty(t, w.Header().Get("Warning"))
+			}
+
+			albums, err := api.Storage.GetAll(context.Background(), nil)
+			require.NoError(t, err)
+
+			titles := []string{}
+			for _, a := range albums {
+				titles = append(titles, a.Title)
+			}
+			require.ElementsMatch(t, tt.expectedTitles, titles)
+		})
+	}
+
+	t.Run("ImportRollbackKeepsExisting", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithImportExport().
+			SetAfterCreateOrUpdate(func(http.ResponseWriter, *http.Request, *Album) *babyapi.ErrResponse {
+				return babyapi.ErrInvalidRequest(errors.New("hook error"))
+			}).
+			WithPostWriteHookPolicy(babyapi.PostWriteHookRollback)
+		require.NoError(t, api.Storage.Set(context.Background(), &Album{DefaultResource: album.DefaultResource, Title: album.Title}))
+
+		body := `{"id":"` + album.GetID() + `","title":"New"}` + "\n" + `{"id":"cqsbrb9jkrnn3bhbaf5g","title":"New"}`
+		r, err := http.NewRequest(http.MethodPost, "/albums/import", strings.NewReader(body))
+		require.NoError(t, err)
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusMultiStatus, w.Result().StatusCode)
+
+		albums, err := api.Storage.GetAll(context.Background(), nil)
+		require.NoError(t, err)
+		require.Len(t, albums, 1)
+		require.Equal(t, "Album1", albums[0].Title)
+	})
+	t.Run("CreateDoesNotReadPreviousWithoutRollback", func(t *testing.T) {
+		storage := &slowStorage{
+			Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+			release: make(chan struct{}),
+			err:     errors.New("read error"),
+		}
+		close(storage.release)
+
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithPostWriteHookPolicy(babyapi.PostWriteHookWarn).
+			SetStorage(storage)
+
+		r, err := http.NewRequest(http.MethodPost, "/albums", strings.NewReader(`{"title":"New"}`))
+		require.NoError(t, err)
+		r.Header.Set("Content-Type", "application/json")
+
+		w := babytest.TestRequest[*Album](t, api, r)
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+		require.Zero(t, storage.reads.Load())
+	})
+}
@@ -0,0 +1,97 @@
+package babyapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// WithReadCoalescing makes concurrent GET and HEAD requests for the same resource share a single read from storage.
+// This reduces load on storage for frequently requested resources. Each request still returns when its own context
+// is done, but the shared read is only cancelled by the first request's deadline, like from WithMethodTimeout, so the
+// other requests can use the result. Only requests with the same storage key and Consistency share reads. Since the
+// resource is shared, handlers and middleware for GET requests must not modify it
+func (a *API[T]) WithReadCoalescing() *API[T] {
+	a.panicIfReadOnly()
+
+	a.readCoalescing = &flightGroup[T]{}
+	return a
+}
+
+// readResource gets the resource from storage. GET and HEAD requests share reads when WithReadCoalescing is used.
+// Other methods always read their own copy since they might modify it
+func (a *API[T]) readResource(r *http.Request, key string) (T, error) {
+	if a.readCoalescing == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return a.getReadStorage().Get(r.Context(), key)
+	}
+
+	// Requests with different consistency levels can get different results from storage
+	flightKey := string(GetConsistencyFromContext(r.Context())) + "\x00" + key
+
+	return a.readCoalescing.Do(r.Context(), flightKey, func(ctx context.Context) (T, error) {
+		return a.getReadStorage().Get(ctx, key)
+	})
+}
+
+// flightGroup runs one call at a time for each key and shares the result with all callers that are waiting for it.
+// It is similar to golang.org/x/sync/singleflight
+type flightGroup[T any] struct {
+	lock  sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Do calls fn unless a call for the key is already in progress, then waits for the result or for ctx to be done. fn
+// gets a context that is not cancelled with ctx since other callers might be waiting for it, but it keeps ctx's
+// deadline so the call can't run forever
+func (g *flightGroup[T]) Do(ctx context.Context, key string, fn func(context.Context) (T, error)) (T, error) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall[T]{}
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall[T]{done: make(chan struct{})}
+		g.calls[key] = call
+
+		callCtx, cancel := context.WithoutCancel(ctx), context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok {
+			callCtx, cancel = context.WithDeadline(callCtx, deadline)
+		}
+
+		go g.run(callCtx, cancel, key, call, fn)
+	}
+	g.lock.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+	}
+}
+
+func (g *flightGroup[T]) run(ctx context.Context, cancel context.CancelFunc, key string, call *flightCall[T], fn func(context.Context) (T, error)) {
+	defer cancel()
+	defer func() {
+		// Panics are returned to all callers as an error since they can't be recovered by the request's middleware
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("panic reading resource: %v", r)
+		}
+
+		g.lock.Lock()
+		delete(g.calls, key)
+		g.lock.Unlock()
+
+		close(call.done)
+	}()
+
+	call.value, call.err = fn(ctx)
+}
@@ -0,0 +1,207 @@
+package babyapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/calvinmclean/babyapi/storage/kv"
+	"github.com/stretchr/testify/require"
+)
+
+// slowStorage blocks reads until release is closed and counts them
+type slowStorage struct {
+	babyapi.Storage[*Album]
+	release chan struct{}
+	reads   atomic.Int64
+	err     error
+}
+
+func (s *slowStorage) Get(ctx context.Context, id string) (*Album, error) {
+	s.reads.Add(1)
+	<-s.release
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.Storage.Get(ctx, id)
+}
+
+func TestReadCoalescing(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"Success", nil, http.StatusOK},
+		{"ErrorForAll", errors.New("storage error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &slowStorage{
+				Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+				release: make(chan struct{}),
+				err:     tt.err,
+			}
+			require.NoError(t, storage.Storage.Set(context.Background(), album))
+
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				SetStorage(storage).
+				WithReadCoalescing()
+
+			router, err := api.Router()
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			statuses := make(chan int, 10)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					r := httptest.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+					w := httptest.NewRecorder()
+					router.ServeHTTP(w, r)
+					statuses <- w.Result().StatusCode
+				}()
+			}
+
+			// All requests wait for the same read
+			time.Sleep(100 * time.Millisecond)
+			require.Equal(t, int64(1), storage.reads.Load())
+			close(storage.release)
+			wg.Wait()
+			close(statuses)
+
+			for status := range statuses {
+				require.Equal(t, tt.expectedStatus, status)
+			}
+		})
+	}
+
+	t.Run("WaiterCancelled", func(t *testing.T) {
+		storage := &slowStorage{
+			Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+			release: make(chan struct{}),
+		}
+		defer close(storage.release)
+		require.NoError(t, storage.Storage.Set(context.Background(), album))
+
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage).
+			WithReadCoalescing()
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		r := httptest.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody).WithContext(ctx)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	})
+}
+
+// contextStorage records the contexts used for reads and blocks until release is closed or the context is done
+type contextStorage struct {
+	babyapi.Storage[*Album]
+	release chan struct{}
+	reads   chan context.Context
+}
+
+func (s *contextStorage) Get(ctx context.Context, id string) (*Album, error) {
+	s.reads <- ctx
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return s.Storage.Get(ctx, id)
+}
+
+func TestReadCoalescingContext(t *testing.T) {
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	newAPI := func(t *testing.T) (*babyapi.API[*Album], *contextStorage) {
+		storage := &contextStorage{
+			Storage: babyapi.NewKVStorage[*Album](kv.NewDefaultDB(), "Albums"),
+			release: make(chan struct{}),
+			reads:   make(chan context.Context, 10),
+		}
+		require.NoError(t, storage.Storage.Set(context.Background(), album))
+
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			SetStorage(storage).
+			WithReadCoalescing()
+		return api, storage
+	}
+
+	t.Run("KeepsDeadline", func(t *testing.T) {
+		api, storage := newAPI(t)
+		api.WithMethodTimeout(http.MethodGet, 20*time.Millisecond)
+		defer close(storage.release)
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody))
+		require.NotEqual(t, http.StatusOK, w.Result().StatusCode)
+
+		ctx := <-storage.reads
+		_, ok := ctx.Deadline()
+		require.True(t, ok)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("shared read was not cancelled at the deadline")
+		}
+	})
+
+	t.Run("SeparateConsistency", func(t *testing.T) {
+		api, storage := newAPI(t)
+		api.WithConsistencyHeader()
+
+		router, err := api.Router()
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for _, consistency := range []string{"", string(babyapi.ConsistencyStrong)} {
+			wg.Add(1)
+			go func(consistency string) {
+				defer wg.Done()
+
+				r := httptest.NewRequest(http.MethodGet, "/albums/"+album.GetID(), http.NoBody)
+				if consistency != "" {
+					r.Header.Set(babyapi.ConsistencyHeader, consistency)
+				}
+				router.ServeHTTP(httptest.NewRecorder(), r)
+			}(consistency)
+		}
+
+		consistencies := []babyapi.Consistency{}
+		for i := 0; i < 2; i++ {
+			select {
+			case ctx := <-storage.reads:
+				consistencies = append(consistencies, babyapi.GetConsistencyFromContext(ctx))
+			case <-time.After(time.Second):
+				t.Fatal("requests with different consistency shared a read")
+			}
+		}
+		require.ElementsMatch(t, []babyapi.Consistency{babyapi.ConsistencyEventual, babyapi.ConsistencyStrong}, consistencies)
+
+		close(storage.release)
+		wg.Wait()
+	})
+}
@@ -25,6 +25,15 @@ type relatedAPI interface {
 	setParent(relatedAPI)
 	getCustomResponseCodeMap() map[string]int
 	isRoot() bool
+	cascadeDelete(http.ResponseWriter, *http.Request, string) CascadeDeleteResults
+	addOpenAPIPaths(string, map[string]any, map[string]any)
+}
+
+// ParentIDGetter can be implemented by resources in nested APIs that reference their parent resources. The name is
+// the parent API's name and false is returned if the resource doesn't reference that parent. It is used by the
+// default PUT handler to make sure the parent IDs match the URL path. Empty parent IDs are rejected
+type ParentIDGetter interface {
+	GetParentID(name string) (string, bool)
 }
 
 // Parent returns the API's parent API
@@ -53,6 +62,36 @@ func (a *API[T]) AddNestedAPI(childAPI RelatedAPI) *API[T] {
 	return a
 }
 
+// validateParentIDs checks that the resource's parent IDs match the parent IDs in the URL path, if the resource
+// implements ParentIDGetter
+func (a *API[T]) validateParentIDs(r *http.Request, resource T) *ErrResponse {
+	getter, ok := any(resource).(ParentIDGetter)
+	if !ok {
+		return nil
+	}
+
+	for parent := a.Parent(); parent != nil; parent = parent.Parent() {
+		if rel, ok := parent.(relatedAPI); ok && rel.isRoot() {
+			break
+		}
+
+		parentID, ok := getter.GetParentID(parent.Name())
+		if !ok {
+			continue
+		}
+
+		if parentID == "" {
+			return ErrInvalidRequest(fmt.Errorf("missing parent ID for %s", parent.Name()))
+		}
+
+		if parentID != parent.GetIDParam(r) {
+			return ErrInvalidRequest(fmt.Errorf("parent ID for %s must match URL path", parent.Name()))
+		}
+	}
+
+	return nil
+}
+
 func (a *API[T]) setParent(parent relatedAPI) {
 	a.parent = parent
 }
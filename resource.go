@@ -3,7 +3,9 @@ package babyapi
 import (
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
+	"net/url"
 
 	"github.com/go-chi/render"
 	"github.com/rs/xid"
@@ -36,6 +38,13 @@ type Patcher[T Resource] interface {
 	Patch(T) *ErrResponse
 }
 
+// Defaultable can be implemented by resources to set default values when they are created with a POST request.
+// ApplyDefaults is called on a separate instance after the request body is bound, and its values are copied to the
+// top-level fields that are omitted from the request
+type Defaultable interface {
+	ApplyDefaults()
+}
+
 // DefaultRenderer implements an empty Render method and can be used to easily create render.Renderer implementations
 // without having to add the method
 type DefaultRenderer struct{}
@@ -129,6 +138,8 @@ type ResourceList[T render.Renderer] struct {
 	Items []T `json:"items"`
 }
 
+func (rl *ResourceList[T]) responseItems() {}
+
 func (rl *ResourceList[T]) Render(w http.ResponseWriter, r *http.Request) error {
 	for _, item := range rl.Items {
 		err := item.Render(w, r)
@@ -139,6 +150,104 @@ func (rl *ResourceList[T]) Render(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
+// defaultResourceListTemplate is used to render the default GetAll response as HTML when no other template is set
+var defaultResourceListTemplate = template.Must(template.New("resourceList").Parse(`<ul>
+{{- range .Items }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>`))
+
+// ResourceListHTML is the data used to execute the template from WithCollectionHTMLTemplate
+type ResourceListHTML struct {
+	// Items contains the rendered HTML for each item in the list
+	Items []template.HTML
+	// Count is the number of items in the list
+	Count int
+	// Query has the request's query parameters so templates can create links that preserve filters
+	Query url.Values
+}
+
+// htmlResourceList is the response from the default GetAll handler. It is rendered the same as a ResourceList, but
+// it can also render its items as HTML using a template. This is kept separate from ResourceList so types embedding
+// a ResourceList keep their own HTML methods
+type htmlResourceList struct {
+	ResourceList[render.Renderer]
+
+	template *template.Template
+
+	// htmlItems is true when the API's resource type can be rendered as HTML, so an empty list is rendered as HTML
+	// even though it has no items to check
+	htmlItems bool
+}
+
+// RenderHTML renders each item using its HTML method and then wraps them using the template. It stops early with an
+// error if the request context is cancelled
+func (rl *htmlResourceList) RenderHTML(r *http.Request) (string, error) {
+	data := ResourceListHTML{
+		Items: []template.HTML{},
+		Count: len(rl.Items),
+		Query: r.URL.Query(),
+	}
+	for _, item := range rl.Items {
+		err := r.Context().Err()
+		if err != nil {
+			return "", err
+		}
+
+		var itemHTML string
+		switch v := item.(type) {
+		case HTMLRenderer:
+			itemHTML, err = v.RenderHTML(r)
+			if err != nil {
+				return "", fmt.Errorf("error rendering item: %w", err)
+			}
+		case HTMLer:
+			itemHTML = v.HTML(r)
+		default:
+			continue
+		}
+
+		// HTMLer output is trusted HTML, just like when it is written directly to the response
+		data.Items = append(data.Items, template.HTML(itemHTML))
+	}
+
+	tmpl := rl.template
+	if tmpl == nil {
+		tmpl = defaultResourceListTemplate
+	}
+
+	return RenderHTML(r.Context(), tmpl, data)
+}
+
+// htmlEnabled is used to only respond with HTML when the items are HTMLers. Otherwise, the list will fall back to
+// the default responder
+func (rl *htmlResourceList) htmlEnabled() bool {
+	if rl.htmlItems {
+		return true
+	}
+
+	if len(rl.Items) == 0 {
+		return false
+	}
+
+	for _, item := range rl.Items {
+		if !isHTMLer(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isHTMLer(v any) bool {
+	switch v.(type) {
+	case HTMLer, HTMLRenderer:
+		return true
+	default:
+		return false
+	}
+}
+
 // AnyResource is intended to create a "generic" Client
 type AnyResource map[string]any
 
@@ -0,0 +1,192 @@
+package babyapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Exampler can be implemented by a Resource to provide example values for documentation, like a generated OpenAPI
+// spec or JSON Schema
+type Exampler[T Resource] interface {
+	Examples() []T
+}
+
+// WithExamples adds example resources for documentation. They are combined with examples from the Exampler
+// interface. Examples are validated when the API's routes are created so they don't drift from the resource type
+func (a *API[T]) WithExamples(examples ...T) *API[T] {
+	a.panicIfReadOnly()
+
+	a.examples = append(a.examples, examples...)
+	return a
+}
+
+// Examples returns the examples from the Exampler interface and WithExamples
+func (a *API[T]) Examples() []T {
+	examples := []T{}
+	if a.instance == nil {
+		return append(examples, a.examples...)
+	}
+
+	exampler, ok := any(a.instance()).(Exampler[T])
+	if ok {
+		examples = append(examples, exampler.Examples()...)
+	}
+
+	return append(examples, a.examples...)
+}
+
+// validateExamples checks that each example's JSON can be decoded back into the resource type without unknown fields
+// and matches the resource's JSON Schema. This catches examples that use custom JSON encoding which no longer matches
+// the type
+func (a *API[T]) validateExamples() []error {
+	errs := []error{}
+	if a.instance == nil {
+		return errs
+	}
+
+	schema := a.typeSchema(reflect.TypeOf(a.instance()), map[reflect.Type]bool{})
+	for i, example := range a.Examples() {
+		err := a.validateExample(example, schema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid example %d: %w", i, err))
+		}
+	}
+
+	return errs
+}
+
+func (a *API[T]) validateExample(example T, schema map[string]any) error {
+	data, err := json.Marshal(example)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(a.instance())
+	if err != nil {
+		return err
+	}
+
+	generic, err := a.exampleJSON(example)
+	if err != nil {
+		return err
+	}
+
+	return validateSchema(schema, generic, "$")
+}
+
+// exampleJSON converts an example to generic JSON in the same format as responses, using the API's time format and
+// field name transform
+func (a *API[T]) exampleJSON(example T) (any, error) {
+	data, err := toGenericJSON(example)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.timeFormat != "" {
+		data, err = formatTimes(example, data, a.timeFormat)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if a.fieldNameTransform != nil {
+		value := reflect.ValueOf(example)
+		data = transformFieldNames(value.Type(), value, data, a.fieldNameTransform, false)
+	}
+
+	return data, nil
+}
+
+// validateSchema checks generic JSON data against a schema from typeSchema, so it only supports the keywords used
+// there. Null is always allowed because pointers, slices, and maps aren't marked as nullable
+func validateSchema(schema map[string]any, data any, path string) error {
+	if data == nil {
+		return nil
+	}
+
+	if !schemaTypeMatches(schema["type"], data) {
+		return fmt.Errorf("%s: expected %v, got %s", path, schema["type"], jsonType(data))
+	}
+
+	switch data := data.(type) {
+	case map[string]any:
+		properties, hasProperties := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for key, value := range data {
+			fieldSchema, ok := properties[key].(map[string]any)
+			switch {
+			case ok:
+			case hasProperties:
+				return fmt.Errorf("%s: unknown field %q", path, key)
+			case additional != nil:
+				fieldSchema = additional
+			default:
+				continue
+			}
+
+			err := validateSchema(fieldSchema, value, path+"."+key)
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		for i, value := range data {
+			err := validateSchema(items, value, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// schemaTypeMatches checks if the data matches the schema's type keyword, which is a string or a list of strings
+func schemaTypeMatches(schemaType, data any) bool {
+	switch schemaType := schemaType.(type) {
+	case string:
+		actual := jsonType(data)
+		return actual == schemaType || (schemaType == "number" && actual == "integer")
+	case []string:
+		return slices.ContainsFunc(schemaType, func(t string) bool {
+			return schemaTypeMatches(t, data)
+		})
+	default:
+		return true
+	}
+}
+
+// jsonType returns the JSON Schema type name for generic JSON data decoded with json.Number
+func jsonType(data any) string {
+	switch data := data.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(data.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", data)
+	}
+}
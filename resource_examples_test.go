@@ -0,0 +1,56 @@
+package babyapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type schemaAlbum struct {
+	Title    string            `json:"title"`
+	Year     int               `json:"year"`
+	Released *time.Time        `json:"released"`
+	Tracks   []string          `json:"tracks"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeFormat    string
+		data          string
+		expectedError string
+	}{
+		{"Valid", "", `{"title": "A", "year": 2000, "released": "2000-01-01T00:00:00Z", "tracks": ["B"], "labels": {"c": "d"}}`, ""},
+		{"NullsAllowed", "", `{"title": "A", "released": null, "tracks": null, "labels": null}`, ""},
+		{"UnixTime", TimeFormatUnix, `{"released": 946684800}`, ""},
+		{"WrongType", "", `{"title": 1}`, "$.title: expected string, got integer"},
+		{"FloatForInteger", "", `{"year": 1.5}`, "$.year: expected integer, got number"},
+		{"UnknownField", "", `{"name": "A"}`, `$: unknown field "name"`},
+		{"WrongItemType", "", `{"tracks": ["B", 1]}`, "$.tracks[1]: expected string, got integer"},
+		{"WrongMapValueType", "", `{"labels": {"c": true}}`, "$.labels.c: expected string, got boolean"},
+		{"WrongTimeFormat", TimeFormatUnix, `{"released": "2000-01-01T00:00:00Z"}`, "$.released: expected [integer null], got string"},
+		{"NotAnObject", "", `[]`, "$: expected object, got array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewAPI("TODOs", "/todos", func() *TODO { return &TODO{} })
+			api.timeFormat = tt.timeFormat
+			schema := api.typeSchema(reflect.TypeOf(schemaAlbum{}), map[reflect.Type]bool{})
+
+			data, err := decodeJSONWithNumbers(strings.NewReader(tt.data))
+			require.NoError(t, err)
+
+			err = validateSchema(schema, data, "$")
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package babyapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultRetryAfter = time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// SetRetryAfter sets the Retry-After header to the duration in seconds, rounded up. This can be used by custom
+// handlers and middleware, like rate limiters, to tell clients when to retry 429 and 503 responses
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	seconds := max(int64(math.Ceil(d.Seconds())), 1)
+	w.Header().Set("Retry-After", fmt.Sprint(seconds))
+}
+
+// SetRetryAfterDate sets the Retry-After header to the time as an HTTP-date
+func SetRetryAfterDate(w http.ResponseWriter, t time.Time) {
+	w.Header().Set("Retry-After", t.UTC().Format(http.TimeFormat))
+}
+
+// WithRetryAfter sets the Retry-After duration for 503 responses from WithMaxInFlight and maintenance mode, and for
+// requests that are received while the API is shutting down when the shutdown deadline is not known. The default is
+// one second
+func (a *API[T]) WithRetryAfter(d time.Duration) *API[T] {
+	a.panicIfReadOnly()
+
+	a.retryAfter = d
+	return a
+}
+
+func (a *API[T]) getRetryAfter() time.Duration {
+	if a.retryAfter <= 0 {
+		return defaultRetryAfter
+	}
+	return a.retryAfter
+}
+
+// shutdownMiddleware rejects requests with 503 Service Unavailable after the API is stopped. When the API is stopped
+// by Serve, Retry-After is the time until the shutdown deadline
+func (a *API[T]) shutdownMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-a.Done():
+		case <-a.context.Done():
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter := a.getRetryAfter()
+		if deadline := a.shutdownDeadline.Load(); deadline > 0 {
+			retryAfter = max(time.Until(time.Unix(0, deadline)), retryAfter)
+		}
+
+		GetLoggerFromContext(r.Context()).Warn("rejecting request because the API is shutting down")
+		SetRetryAfter(w, retryAfter)
+		_ = render.Render(w, r, ErrServiceUnavailableResponse)
+	})
+}
@@ -0,0 +1,93 @@
+package babyapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		expected string
+	}{
+		{"WholeSeconds", 30 * time.Second, "30"},
+		{"RoundedUp", 1500 * time.Millisecond, "2"},
+		{"Minimum", 0, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			babyapi.SetRetryAfter(w, tt.duration)
+			require.Equal(t, tt.expected, w.Header().Get("Retry-After"))
+		})
+	}
+
+	t.Run("Date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		babyapi.SetRetryAfterDate(w, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		require.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", w.Header().Get("Retry-After"))
+	})
+}
+
+func TestWithRetryAfterMaxInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithMaxInFlight(1).
+		WithRetryAfter(5*time.Second).
+		AddCustomRoute(http.MethodGet, "/block", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/albums/block", http.NoBody))
+		close(done)
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums", http.NoBody))
+	require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+	require.Equal(t, "5", w.Result().Header.Get("Retry-After"))
+
+	close(release)
+	<-done
+}
+
+func TestShutdownRetryAfter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+		WithContext(ctx).
+		WithRetryAfter(3 * time.Second)
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums", http.NoBody))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	cancel()
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/albums", http.NoBody))
+	require.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+	require.Equal(t, "3", w.Result().Header.Get("Retry-After"))
+	require.Equal(t, `{"status":"Service unavailable.","error_code":"service_unavailable"}`, strings.TrimSpace(w.Body.String()))
+}
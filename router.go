@@ -1,9 +1,14 @@
 package babyapi
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -48,25 +53,55 @@ type HTMLer interface {
 	HTML(*http.Request) string
 }
 
+// HTMLRenderer is an alternative to HTMLer that returns an error instead of panicking. When a response implements
+// both, this is preferred. If the error is caused by the request context being cancelled, the response status is
+// StatusClientClosedRequest. Otherwise, it is an internal server error
+type HTMLRenderer interface {
+	RenderHTML(*http.Request) (string, error)
+}
+
+// conditionalHTMLer is implemented by HTMLers that are not always able to render HTML, like the default GetAll
+// response containing items that are not HTMLers
+type conditionalHTMLer interface {
+	htmlEnabled() bool
+}
+
 // Create API routes on the given router
 func (a *API[T]) Route(r chi.Router) error {
 	a.readOnly.TryLock()
 
-	if len(a.errors) > 0 {
-		return BuilderError{a.errors}
+	errs := append(slices.Clone(a.errors), a.validateExamples()...)
+	errs = append(errs, a.validateStorageKey()...)
+	if len(errs) > 0 {
+		return BuilderError{errs}
 	}
 
 	respondOnce.Do(func() {
+		render.Decode = decodeRequest
 		render.Respond = func(w http.ResponseWriter, r *http.Request, v interface{}) {
-			if render.GetAcceptedContentType(r) == render.ContentTypeHTML {
-				htmler, ok := v.(HTMLer)
-				if ok {
+			if render.GetAcceptedContentType(r) == render.ContentTypeHTML && htmlEnabled(v) {
+				switch htmler := v.(type) {
+				case HTMLRenderer:
+					respondHTML(w, r, htmler)
+					return
+				case HTMLer:
 					render.HTML(w, r, htmler.HTML(r))
 					return
 				}
 			}
 
-			render.DefaultResponder(w, r, v)
+			if !isJSONResponse(r, v) {
+				render.DefaultResponder(w, r, v)
+				return
+			}
+
+			formatted, err := formatResponse(r, v)
+			if err != nil {
+				respondSerializationError(w, r, v, err)
+				return
+			}
+
+			respondJSON(w, r, formatted)
 		}
 	})
 
@@ -75,6 +110,44 @@ func (a *API[T]) Route(r chi.Router) error {
 		a.DefaultMiddleware(r)
 	}
 
+	if a.timeFormat != "" {
+		r = r.With(a.timeFormatMiddleware)
+	}
+
+	if a.fieldNameTransform != nil {
+		r = r.With(a.fieldNameTransformMiddleware)
+	}
+
+	if a.jsonCodec != nil {
+		r = r.With(a.jsonCodecMiddleware)
+	}
+
+	if a.fieldSelection {
+		r = r.With(a.fieldSelectionMiddleware)
+	}
+
+	if a.absoluteURLs {
+		r = r.With(a.absoluteURLMiddleware)
+	}
+
+	if a.strictContentType {
+		r = r.With(strictContentTypeMiddleware(a.acceptedContentTypes()))
+	}
+
+	if a.consistencyHeader {
+		r = r.With(consistencyMiddleware)
+	}
+
+	if a.instance != nil && hasFieldAccessTags(reflect.TypeOf(a.instance())) {
+		r = r.With(a.fieldAccessMiddleware)
+	}
+
+	if a.devMode {
+		r = r.With(devModeMiddleware)
+	}
+
+	r = r.With(a.maintenanceMiddleware)
+
 	for _, m := range a.middlewares {
 		r = r.With(m)
 	}
@@ -83,6 +156,12 @@ func (a *API[T]) Route(r chi.Router) error {
 		a.doCustomRoutes(r, a.rootRoutes)
 	}
 
+	if a.parent == nil && a.openAPIVersion != "" {
+		r.Get("/openapi", a.defaultOpenAPI(""))
+		r.Get("/openapi.json", a.defaultOpenAPI("json"))
+		r.Get("/openapi.yaml", a.defaultOpenAPI("yaml"))
+	}
+
 	var returnErr error
 	r.Route(a.base, func(r chi.Router) {
 		if a.rootAPI {
@@ -90,15 +169,52 @@ func (a *API[T]) Route(r chi.Router) error {
 			return
 		}
 
-		routeIfNotNil(r.With(a.requestBodyMiddleware).Post, "/", a.Post)
-		routeIfNotNil(r.Get, "/", a.GetAll)
+		collectionRouter, idRouter := r, r
+		if len(a.methodTimeouts) > 0 {
+			collectionRouter = r.With(a.methodTimeoutMiddleware(MethodGetAll))
+			idRouter = r.With(a.methodTimeoutMiddleware(http.MethodGet))
+		}
 
-		r.With(a.resourceExistsMiddleware).Route(fmt.Sprintf("/{%s}", a.IDParamKey()), func(r chi.Router) {
+		if a.bulkCreate {
+			routeIfNotNil(collectionRouter.With(a.bulkArrayMiddleware, a.requestBodyMiddleware).Post, "/", a.Post)
+			collectionRouter.Post("/bulk", a.defaultBulkCreate())
+		} else {
+			routeIfNotNil(collectionRouter.With(a.requestBodyMiddleware).Post, "/", a.Post)
+		}
+		if len(a.aggregationFields) > 0 {
+			collectionRouter.Get("/aggregate", a.defaultAggregate())
+		}
+		if a.schemaEndpoint {
+			collectionRouter.Get("/schema", a.defaultSchema())
+		}
+		if a.importExport {
+			collectionRouter.Get("/export", a.defaultExport())
+			collectionRouter.Post("/import", a.defaultImport())
+		}
+		routeIfNotNil(collectionRouter.Get, "/", a.GetAll)
+		if a.GetAll != nil {
+			collectionRouter.Head("/", a.defaultHeadAll())
+		}
+
+		if a.idType != nil {
+			idRouter = idRouter.With(a.idTypeMiddleware)
+		}
+
+		if a.locker != nil {
+			idRouter = idRouter.With(a.resourceLockMiddleware, a.resourceExistsMiddleware)
+		} else {
+			idRouter = idRouter.With(a.resourceExistsMiddleware)
+		}
+
+		idRouter.Route(fmt.Sprintf("/{%s}", a.IDParamKey()), func(r chi.Router) {
 			for _, m := range a.idMiddlewares {
 				r = r.With(m)
 			}
 
 			routeIfNotNil(r.Get, "/", a.Get)
+			if a.Get != nil {
+				r.Head("/", a.defaultHead())
+			}
 			routeIfNotNil(r.Delete, "/", a.Delete)
 			routeIfNotNil(r.With(a.requestBodyMiddleware).Put, "/", a.Put)
 			routeIfNotNil(r.With(a.requestBodyMiddleware).Patch, "/", a.Patch)
@@ -144,6 +260,122 @@ func (a *API[T]) rootAPIRoutes(r chi.Router) error {
 	return nil
 }
 
+// htmlEnabled checks if the response can be rendered as HTML
+func htmlEnabled(v any) bool {
+	conditional, ok := v.(conditionalHTMLer)
+	return !ok || conditional.htmlEnabled()
+}
+
+// respondHTML uses the HTMLRenderer to respond and handles errors from rendering
+func respondHTML(w http.ResponseWriter, r *http.Request, htmlRenderer HTMLRenderer) {
+	result, err := htmlRenderer.RenderHTML(r)
+	if err == nil {
+		render.HTML(w, r, result)
+		return
+	}
+
+	logger := GetLoggerFromContext(r.Context())
+	if errors.Is(err, context.Canceled) {
+		logger.Warn("request cancelled while rendering HTML", "error", err)
+		w.WriteHeader(StatusClientClosedRequest)
+		return
+	}
+
+	logger.Error("error rendering HTML", "error", err)
+	_ = render.Render(w, r, InternalServerError(err))
+}
+
+// isJSONResponse checks if the response will be rendered as JSON by render.DefaultResponder
+func isJSONResponse(r *http.Request, v any) bool {
+	if render.GetAcceptedContentType(r) == render.ContentTypeXML {
+		return false
+	}
+
+	return v == nil || reflect.TypeOf(v).Kind() != reflect.Chan
+}
+
+// formatResponse marshals the response to generic JSON data once and applies the time format, write-only fields,
+// field name transform, and field selection to it. The response is returned unchanged if none of these are used
+func formatResponse(r *http.Request, v any) (any, error) {
+	if v == nil || !hasResponseTransforms(r) {
+		return v, nil
+	}
+
+	data, err := toGenericJSON(v)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = formatResponseTimes(r, v, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data = removeWriteOnlyFields(r, v, data)
+	data = transformResponseFields(r, v, data)
+	data = projectResponseFields(r, v, data)
+
+	return data, nil
+}
+
+func hasResponseTransforms(r *http.Request) bool {
+	ctx := r.Context()
+	return getTimeFormatFromContext(ctx) != "" ||
+		fieldAccessEnabled(r) ||
+		getFieldNameTransformFromContext(ctx) != nil ||
+		GetSelectedFieldsFromContext(ctx) != nil
+}
+
+// toGenericJSON marshals the value and decodes it to maps and slices so it can be changed before it is encoded
+func toGenericJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSONWithNumbers(bytes.NewReader(data))
+}
+
+// respondJSON is the same as render.JSON, except it responds with an ErrResponse if the value can't be serialized. It
+// uses the JSONCodec from the request context
+func respondJSON(w http.ResponseWriter, r *http.Request, v any) {
+	buf := &bytes.Buffer{}
+	err := getJSONCodecFromContext(r.Context()).NewEncoder(buf).Encode(v)
+	if err != nil {
+		respondSerializationError(w, r, v, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if status, ok := r.Context().Value(render.StatusCtxKey).(int); ok {
+		w.WriteHeader(status)
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+// respondSerializationError logs details about a response that failed to serialize and responds with a 500 error.
+// The error details are only included in the response in dev mode
+func respondSerializationError(w http.ResponseWriter, r *http.Request, v any, err error) {
+	logArgs := []any{"type", fmt.Sprintf("%T", v), "error", err}
+	if resource, ok := v.(interface{ GetID() string }); ok {
+		logArgs = append(logArgs, "id", resource.GetID())
+	}
+	GetLoggerFromContext(r.Context()).Error("error serializing response", logArgs...)
+
+	httpErr := &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusInternalServerError,
+		StatusText:     "Error serializing response.",
+		Code:           ErrCodeSerialization,
+	}
+	if isDevMode(r.Context()) {
+		httpErr.ErrorText = fmt.Sprintf("error serializing %T: %v", v, err)
+	}
+
+	render.Status(r, httpErr.HTTPStatusCode)
+	respondJSON(w, r, httpErr)
+}
+
 // Create a new router with API routes
 func (a *API[T]) Router() (chi.Router, error) {
 	r := chi.NewRouter()
@@ -163,15 +395,39 @@ func (a *API[T]) defaultGet() http.HandlerFunc {
 	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
 		logger := GetLoggerFromContext(r.Context())
 
-		resource, httpErr := a.GetRequestedResource(r)
+		var resource T
+		var httpErr *ErrResponse
+		if _, _, ok := a.projectingStorage(r); ok {
+			// Only the selected fields were read by resourceExistsMiddleware, so it is not read again
+			var err error
+			resource, err = a.GetResourceFromContext(r.Context())
+			if err != nil {
+				httpErr = InternalServerError(err)
+			}
+		} else {
+			resource, httpErr = a.GetRequestedResource(r)
+		}
 		if httpErr != nil {
 			logger.Error("error getting requested resource", "error", httpErr.Error())
 			return httpErr
 		}
 
+		resp := a.wrapResponse(r, resource)
+		if a.etags {
+			etag, err := resourceETag(r, resp)
+			if err != nil {
+				logger.Error("error creating ETag", "error", err)
+				return InternalServerError(err)
+			}
+
+			if notModified(w, r, etag) {
+				return nil
+			}
+		}
+
 		render.Status(r, a.responseCodes[http.MethodGet])
 
-		return a.responseWrapper(resource)
+		return resp
 	})
 }
 
@@ -179,13 +435,47 @@ func (a *API[T]) defaultGetAll() http.HandlerFunc {
 	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
 		logger := GetLoggerFromContext(r.Context())
 
-		resources, err := a.Storage.GetAll(r.Context(), r.URL.Query())
+		versioned, useVersion := a.getReadStorage().(VersionedStorage)
+		useVersion = useVersion && a.etags
+		if useVersion {
+			version, err := versioned.Version(r.Context())
+			if err != nil {
+				logger.Error("error getting storage version", "error", err)
+				return InternalServerError(err)
+			}
+
+			if notModified(w, r, collectionETag(r, []byte(version))) {
+				return nil
+			}
+		}
+
+		filter, err := a.getAllFilterFunc(r)
+		if err != nil {
+			logger.Error("error parsing filters", "error", err)
+			return ErrInvalidRequest(err)
+		}
+
+		sortFields, err := a.getSortFields(r)
+		if err != nil {
+			logger.Error("error parsing sort", "error", err)
+			return ErrInvalidRequest(err)
+		}
+
+		var resources []T
+		projecting, fields, ok := a.projectingStorage(r)
+		// Only read selected fields when there are no filters or sorting since they might use other fields
+		if ok && filter == nil && len(sortFields) == 0 {
+			resources, err = projecting.GetAllFields(r.Context(), r.URL.Query(), fields)
+		} else {
+			resources, err = a.getReadStorage().GetAll(r.Context(), r.URL.Query())
+		}
 		if err != nil {
 			logger.Error("error getting resources", "error", err)
 			return InternalServerError(err)
 		}
 
-		resources = a.getAllFilter(r).Filter(resources)
+		resources = filter.Filter(resources)
+		sortResources(resources, sortFields)
 		logger.Debug("responding with resources", "count", len(resources))
 
 		var resp render.Renderer
@@ -194,9 +484,25 @@ func (a *API[T]) defaultGetAll() http.HandlerFunc {
 		} else {
 			items := []render.Renderer{}
 			for _, item := range resources {
-				items = append(items, a.responseWrapper(item))
+				items = append(items, a.wrapResponse(r, item))
+			}
+			resp = &htmlResourceList{
+				ResourceList: ResourceList[render.Renderer]{Items: items},
+				template:     a.collectionHTMLTemplate,
+				htmlItems:    isHTMLer(*new(T)),
+			}
+		}
+
+		if a.etags && !useVersion {
+			data, err := representation(r, resp)
+			if err != nil {
+				logger.Error("error creating ETag", "error", err)
+				return InternalServerError(err)
+			}
+
+			if notModified(w, r, collectionETag(r, data)) {
+				return nil
 			}
-			resp = &ResourceList[render.Renderer]{Items: items}
 		}
 
 		render.Status(r, a.responseCodes[MethodGetAll])
@@ -207,29 +513,55 @@ func (a *API[T]) defaultGetAll() http.HandlerFunc {
 
 func (a *API[T]) defaultPost() http.HandlerFunc {
 	return a.ReadRequestBodyAndDo(func(w http.ResponseWriter, r *http.Request, resource T) (T, *ErrResponse) {
-		logger := GetLoggerFromContext(r.Context())
-
-		httpErr := a.onCreateOrUpdate(w, r, resource)
+		httpErr := a.createResource(w, r, resource)
 		if httpErr != nil {
 			return *new(T), httpErr
 		}
 
-		logger.Info("storing resource", "resource", resource)
-		err := a.Storage.Set(r.Context(), resource)
-		if err != nil {
-			logger.Error("error storing resource", "error", err)
-			return *new(T), InternalServerError(err)
-		}
+		w.Header().Set("Location", AbsoluteURL(r, collectionPath(a, r)+"/"+resource.GetID()))
+		render.Status(r, a.responseCodes[http.MethodPost])
+
+		return resource, nil
+	})
+}
+
+// createResource runs the create/update hooks and stores a new resource. IDs are only generated for POST requests
+// since imports use PUT to keep the IDs from the export
+func (a *API[T]) createResource(w http.ResponseWriter, r *http.Request, resource T) *ErrResponse {
+	logger := GetLoggerFromContext(r.Context())
 
-		httpErr = a.afterCreateOrUpdate(w, r, resource)
+	if r.Method == http.MethodPost {
+		httpErr := a.generateID(r, resource)
 		if httpErr != nil {
-			return *new(T), httpErr
+			return httpErr
 		}
+	}
 
-		render.Status(r, a.responseCodes[http.MethodPost])
+	httpErr := a.onCreateOrUpdate(w, r, resource)
+	if httpErr != nil {
+		return httpErr
+	}
 
-		return resource, nil
-	})
+	r, httpErr = a.recordPreviousResource(r, resource.GetID())
+	if httpErr != nil {
+		return httpErr
+	}
+
+	logger.Info("storing resource", "resource", resource)
+	err := a.getWriteStorage().Set(r.Context(), resource)
+	if err != nil {
+		logger.Error("error storing resource", "error", err)
+		return InternalServerError(err)
+	}
+
+	httpErr = a.afterWrite(w, r, resource)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	a.emitChangeEvent(r, resource.GetID(), resource, false)
+
+	return nil
 }
 
 func (a *API[T]) defaultPut() http.HandlerFunc {
@@ -240,23 +572,37 @@ func (a *API[T]) defaultPut() http.HandlerFunc {
 			return *new(T), ErrInvalidRequest(fmt.Errorf("id must match URL path"))
 		}
 
-		httpErr := a.onCreateOrUpdate(w, r, resource)
+		httpErr := a.validateParentIDs(r, resource)
+		if httpErr != nil {
+			return *new(T), httpErr
+		}
+
+		// resourceExistsMiddleware records if the resource does not exist. Otherwise, it might exist, so it is not
+		// created since reading it could have failed
+		if ifNoneMatchAny(r) && !a.resourceNotFoundInContext(r.Context()) {
+			logger.Info("not replacing resource that might exist because of If-None-Match header")
+			return *new(T), ErrPreconditionFailedResponse
+		}
+
+		httpErr = a.onCreateOrUpdate(w, r, resource)
 		if httpErr != nil {
 			return *new(T), httpErr
 		}
 
 		logger.Info("storing resource", "resource", resource)
-		err := a.Storage.Set(r.Context(), resource)
+		err := a.getWriteStorage().Set(r.Context(), resource)
 		if err != nil {
 			logger.Error("error storing resource", "error", err)
 			return *new(T), InternalServerError(err)
 		}
 
-		httpErr = a.afterCreateOrUpdate(w, r, resource)
+		httpErr = a.afterWrite(w, r, resource)
 		if httpErr != nil {
 			return *new(T), httpErr
 		}
 
+		a.emitChangeEvent(r, resource.GetID(), resource, false)
+
 		render.Status(r, a.responseCodes[http.MethodPut])
 
 		return resource, nil
@@ -264,47 +610,81 @@ func (a *API[T]) defaultPut() http.HandlerFunc {
 }
 
 func (a *API[T]) defaultPatch() http.HandlerFunc {
-	return a.ReadRequestBodyAndDo(func(w http.ResponseWriter, r *http.Request, patchRequest T) (T, *ErrResponse) {
+	return Handler(func(w http.ResponseWriter, r *http.Request) render.Renderer {
 		logger := GetLoggerFromContext(r.Context())
 
+		patchRequest, httpErr := a.GetFromRequest(r)
+		if httpErr != nil {
+			return httpErr
+		}
+
 		resource, httpErr := a.GetRequestedResource(r)
 		if httpErr != nil {
 			logger.Error("error getting requested resource", "error", httpErr.Error())
-			return *new(T), httpErr
+			return httpErr
 		}
 
 		patcher, ok := any(resource).(Patcher[T])
 		if !ok {
-			return *new(T), ErrMethodNotAllowedResponse
+			return ErrMethodNotAllowedResponse
+		}
+
+		// Keep a copy of the original resource so only changed fields can be returned
+		minimalChanges := preferMinimalChanges(r)
+		var before map[string]any
+		if minimalChanges {
+			var err error
+			before, err = toJSONMap(resource)
+			if err != nil {
+				logger.Error("error copying resource before patching", "error", err)
+				return InternalServerError(err)
+			}
 		}
 
 		httpErr = patcher.Patch(patchRequest)
 		if httpErr != nil {
 			logger.Error("error patching resource", "error", httpErr.Error())
-			return *new(T), httpErr
+			return httpErr
 		}
 
 		httpErr = a.onCreateOrUpdate(w, r, resource)
 		if httpErr != nil {
-			return *new(T), httpErr
+			return httpErr
 		}
 
 		logger.Info("storing updated resource", "resource", resource)
 
-		err := a.Storage.Set(r.Context(), resource)
+		err := a.getWriteStorage().Set(r.Context(), resource)
 		if err != nil {
 			logger.Error("error storing updated resource", "error", err)
-			return *new(T), InternalServerError(err)
+			return InternalServerError(err)
 		}
 
-		httpErr = a.afterCreateOrUpdate(w, r, resource)
+		httpErr = a.afterWrite(w, r, resource)
 		if httpErr != nil {
-			return *new(T), httpErr
+			return httpErr
 		}
 
+		a.emitChangeEvent(r, resource.GetID(), resource, false)
+
 		render.Status(r, a.responseCodes[http.MethodPatch])
 
-		return resource, nil
+		if minimalChanges {
+			changed, err := Diff(before, resource)
+			if err != nil {
+				logger.Error("error getting changed fields", "error", err)
+				return InternalServerError(err)
+			}
+			changed["id"] = resource.GetID()
+
+			// ChangedFields only has the JSON fields, so the resource's type is used to find write-only fields
+			removeFields(reflect.TypeOf(resource), reflect.Value{}, map[string]any(changed), FieldAccessWriteOnly)
+
+			w.Header().Set("Preference-Applied", PreferMinimalChanges)
+			return changed
+		}
+
+		return a.wrapResponse(r, resource)
 	})
 }
 
@@ -321,7 +701,7 @@ func (a *API[T]) defaultDelete() http.HandlerFunc {
 
 		logger.Info("deleting resource", "id", id)
 
-		err := a.Storage.Delete(r.Context(), id)
+		err := a.getWriteStorage().Delete(r.Context(), a.storageKey(r, id))
 		if err != nil {
 			logger.Error("error deleting resource", "error", err)
 
@@ -332,12 +712,26 @@ func (a *API[T]) defaultDelete() http.HandlerFunc {
 			return InternalServerError(err)
 		}
 
+		// Children are kept when the resource is only soft-deleted since it still exists
+		cascadeResults := CascadeDeleteResults{}
+		previous, err := a.GetResourceFromContext(r.Context())
+		if err != nil || !isSoftDelete(previous) {
+			cascadeResults = a.cascadeDeleteChildren(w, r, id)
+		}
+
 		httpErr = a.afterDelete(w, r)
 		if httpErr != nil {
 			logger.Error("error executing after func", "error", httpErr)
 			return httpErr
 		}
 
+		a.emitChangeEvent(r, id, *new(T), true)
+
+		if cascadeResults.failed() {
+			logger.Warn("some child resources could not be deleted", "id", id)
+			return cascadeResults
+		}
+
 		w.WriteHeader(a.responseCodes[http.MethodDelete])
 		return nil
 	})
@@ -7,19 +7,84 @@ import (
 	"sync"
 )
 
+// EventBackpressurePolicy controls what happens when a server-sent events subscriber can't keep up with new events
+type EventBackpressurePolicy int
+
+const (
+	// EventBackpressureBlock waits for every subscriber to receive each event. This is the default, but a slow
+	// subscriber will block publishers
+	EventBackpressureBlock EventBackpressurePolicy = iota
+	// EventBackpressureDropOldest removes the oldest buffered event to make room for the new event
+	EventBackpressureDropOldest
+	// EventBackpressureDropNewest drops the new event when the subscriber's buffer is full
+	EventBackpressureDropNewest
+	// EventBackpressureDisconnect closes the subscriber's connection when its buffer is full
+	EventBackpressureDisconnect
+)
+
+type eventBackpressure struct {
+	policy     EventBackpressurePolicy
+	bufferSize int
+}
+
+// WithEventBackpressure gives each server-sent events subscriber a queue of bufferSize events and uses the policy
+// when the queue is full. Publishers never wait on subscribers unless the policy is EventBackpressureBlock
+func (a *API[T]) WithEventBackpressure(policy EventBackpressurePolicy, bufferSize int) *API[T] {
+	a.panicIfReadOnly()
+
+	a.eventBackpressure = eventBackpressure{policy, bufferSize}
+	return a
+}
+
+// defaultNonBlockingBufferSize is the subscriber queue size for non-blocking broadcast channels that don't have a
+// buffer size from WithEventBackpressure
+const defaultNonBlockingBufferSize = 16
+
 type broadcastChannel[T any] struct {
-	listeners []chan T
-	lock      sync.RWMutex
+	listeners    []chan T
+	lock         sync.RWMutex
+	backpressure *eventBackpressure
+
+	// nonBlocking uses EventBackpressureDropOldest instead of EventBackpressureBlock so publishers never wait on
+	// subscribers
+	nonBlocking bool
 }
 
 func (bc *broadcastChannel[T]) GetListener() chan T {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
-	newChan := make(chan T)
+	newChan := make(chan T, bc.bufferSize())
 	bc.listeners = append(bc.listeners, newChan)
 	return newChan
 }
 
+func (bc *broadcastChannel[T]) policy() EventBackpressurePolicy {
+	policy := EventBackpressureBlock
+	if bc.backpressure != nil {
+		policy = bc.backpressure.policy
+	}
+
+	if policy == EventBackpressureBlock && bc.nonBlocking {
+		return EventBackpressureDropOldest
+	}
+	return policy
+}
+
+func (bc *broadcastChannel[T]) bufferSize() int {
+	if bc.policy() == EventBackpressureBlock {
+		return 0
+	}
+
+	size := 0
+	if bc.backpressure != nil {
+		size = bc.backpressure.bufferSize
+	}
+	if size <= 0 && bc.nonBlocking {
+		return defaultNonBlockingBufferSize
+	}
+	return max(size, 1)
+}
+
 func (bc *broadcastChannel[T]) RemoveListener(removeChan chan T) {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
@@ -35,9 +100,45 @@ func (bc *broadcastChannel[T]) RemoveListener(removeChan chan T) {
 
 func (bc *broadcastChannel[T]) SendToAll(input T) {
 	bc.lock.RLock()
-	defer bc.lock.RUnlock()
+	policy := bc.policy()
+	disconnect := []chan T{}
 	for _, listener := range bc.listeners {
-		listener <- input
+		if policy == EventBackpressureBlock {
+			listener <- input
+			continue
+		}
+
+		if trySend(listener, input) {
+			continue
+		}
+
+		switch policy {
+		case EventBackpressureDropOldest:
+			// Other publishers can fill the slot between the drop and the send, so keep dropping until the event fits
+			for !trySend(listener, input) {
+				select {
+				case <-listener:
+				default:
+				}
+			}
+		case EventBackpressureDisconnect:
+			disconnect = append(disconnect, listener)
+		}
+	}
+	bc.lock.RUnlock()
+
+	for _, listener := range disconnect {
+		bc.RemoveListener(listener)
+	}
+}
+
+// trySend sends to the channel without blocking and returns false if the channel is full
+func trySend[T any](c chan T, input T) bool {
+	select {
+	case c <- input:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -72,7 +173,7 @@ func (sse *ServerSentEvent) Write(w http.ResponseWriter) {
 // AddServerSentEventHandler is a shortcut for HandleServerSentEvents that automatically creates and returns
 // the events channel and adds a custom handler for GET requests matching the provided pattern
 func (a *API[T]) AddServerSentEventHandler(pattern string) chan *ServerSentEvent {
-	eventsBroadcastChannel := broadcastChannel[*ServerSentEvent]{}
+	eventsBroadcastChannel := broadcastChannel[*ServerSentEvent]{backpressure: &a.eventBackpressure}
 
 	a.AddCustomRoute(http.MethodGet, pattern, a.HandleServerSentEvents(&eventsBroadcastChannel))
 
@@ -91,7 +192,11 @@ func (a *API[T]) HandleServerSentEvents(EventsBroadcastChannel *broadcastChannel
 
 		for {
 			select {
-			case e := <-events:
+			case e, ok := <-events:
+				// The listener is closed if it was disconnected for falling behind
+				if !ok {
+					return
+				}
 				e.Write(w)
 			case <-r.Context().Done():
 				return
@@ -0,0 +1,80 @@
+package babyapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastChannelBackpressure(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           EventBackpressurePolicy
+		expected         []int
+		expectDisconnect bool
+	}{
+		{"DropOldest", EventBackpressureDropOldest, []int{3, 4}, false},
+		{"DropNewest", EventBackpressureDropNewest, []int{1, 2}, false},
+		{"Disconnect", EventBackpressureDisconnect, []int{1, 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := &broadcastChannel[int]{backpressure: &eventBackpressure{tt.policy, 2}}
+			listener := bc.GetListener()
+
+			sent := make(chan struct{})
+			go func() {
+				defer close(sent)
+				for i := 1; i <= 4; i++ {
+					bc.SendToAll(i)
+				}
+			}()
+
+			select {
+			case <-sent:
+			case <-time.After(time.Second):
+				t.Fatal("publisher was blocked by slow subscriber")
+			}
+
+			received := []int{}
+			for i := range listener {
+				received = append(received, i)
+				if len(listener) == 0 && !tt.expectDisconnect {
+					break
+				}
+			}
+			require.Equal(t, tt.expected, received)
+
+			bc.lock.RLock()
+			defer bc.lock.RUnlock()
+			if tt.expectDisconnect {
+				require.Empty(t, bc.listeners)
+			} else {
+				require.Len(t, bc.listeners, 1)
+			}
+		})
+	}
+}
+
+func TestBroadcastChannelDropOldestConcurrentPublishers(t *testing.T) {
+	bc := &broadcastChannel[int]{backpressure: &eventBackpressure{EventBackpressureDropOldest, 1}}
+	listener := bc.GetListener()
+
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				bc.SendToAll(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The last publisher's event is never dropped, so the buffer always ends up full
+	require.Len(t, listener, 1)
+}
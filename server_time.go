@@ -0,0 +1,77 @@
+package babyapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	// ServerTimeField is the response field with the server's time when a resource was created or modified
+	ServerTimeField = "server_time"
+	// ResourceVersionField is the response field with the version of a resource after it was created or modified. It
+	// is a hash of the resource's contents, so it only changes when the resource changes
+	ResourceVersionField = "resource_version"
+)
+
+// WithServerTime adds the server's time and the resource's version to responses for POST, PUT, and PATCH requests so
+// clients can reconcile optimistic updates without relying on their own clock. The fields are added the same way as
+// computed fields and the time uses the format from WithTimeFormat
+func (a *API[T]) WithServerTime() *API[T] {
+	a.panicIfReadOnly()
+
+	a.serverTime = true
+	return a
+}
+
+// WithClock sets the function used to get the current time. This defaults to time.Now and is useful for testing
+func (a *API[T]) WithClock(now func() time.Time) *API[T] {
+	a.panicIfReadOnly()
+
+	a.now = now
+	return a
+}
+
+// addServerTimeFields returns a copy of fields with the server time and resource version if the request modifies
+// a resource
+func (a *API[T]) addServerTimeFields(r *http.Request, resource T, fields map[string]any) map[string]any {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fields
+	}
+
+	result := make(map[string]any, len(fields)+2)
+	for key, value := range fields {
+		result[key] = value
+	}
+
+	now := a.now().UTC()
+	if format := getTimeFormatFromContext(r.Context()); format != "" {
+		result[ServerTimeField] = formatTime(now, format)
+	} else {
+		result[ServerTimeField] = now.Format(time.RFC3339Nano)
+	}
+
+	version, err := resourceVersion(resource)
+	if err != nil {
+		GetLoggerFromContext(r.Context()).Warn("error getting resource version", "error", err)
+	} else {
+		result[ResourceVersionField] = version
+	}
+
+	return result
+}
+
+// resourceVersion hashes the resource's JSON to create a version that changes when the resource changes
+func resourceVersion(resource any) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,91 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	album := &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Album1"}
+
+	tests := []struct {
+		name               string
+		timeFormat         string
+		method             string
+		path               string
+		body               string
+		expectedCode       int
+		expectedServerTime any
+	}{
+		{"Post", "", http.MethodPost, "/albums", `{"title":"New"}`, http.StatusCreated, "2024-01-02T03:04:05Z"},
+		{"Put", "", http.MethodPut, "/albums/" + album.GetID(), `{"id":"` + album.GetID() + `","title":"Updated"}`, http.StatusOK, "2024-01-02T03:04:05Z"},
+		{"Patch", "", http.MethodPatch, "/albums/" + album.GetID(), `{"title":"Patched"}`, http.StatusOK, "2024-01-02T03:04:05Z"},
+		{"PostUnixTimeFormat", babyapi.TimeFormatUnix, http.MethodPost, "/albums", `{"title":"New"}`, http.StatusCreated, float64(1704164645)},
+		{"GetDoesNotIncludeServerTime", "", http.MethodGet, "/albums/" + album.GetID(), "", http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+				WithServerTime().
+				WithClock(func() time.Time { return now })
+			if tt.timeFormat != "" {
+				api.WithTimeFormat(tt.timeFormat)
+			}
+			require.NoError(t, api.Storage.Set(context.Background(), album))
+
+			r, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, tt.expectedCode, w.Result().StatusCode)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, tt.expectedServerTime, resp[babyapi.ServerTimeField])
+
+			if tt.expectedServerTime == nil {
+				require.NotContains(t, resp, babyapi.ResourceVersionField)
+				return
+			}
+			require.Regexp(t, "^[0-9a-f]{64}$", resp[babyapi.ResourceVersionField])
+		})
+	}
+
+	t.Run("VersionOnlyChangesWithResource", func(t *testing.T) {
+		api := babyapi.NewAPI("Albums", "/albums", func() *Album { return &Album{} }).
+			WithServerTime()
+		require.NoError(t, api.Storage.Set(context.Background(), album))
+
+		put := func(title string) any {
+			r, err := http.NewRequest(http.MethodPut, "/albums/"+album.GetID(), bytes.NewBufferString(`{"id":"`+album.GetID()+`","title":"`+title+`"}`))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Album](t, api, r)
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			return resp[babyapi.ResourceVersionField]
+		}
+
+		version := put("Updated")
+		require.NotEmpty(t, version)
+
+		// Creating another resource doesn't change this resource's version
+		require.NoError(t, api.Storage.Set(context.Background(), &Album{DefaultResource: babyapi.NewDefaultResource(), Title: "Other"}))
+		require.Equal(t, version, put("Updated"))
+		require.NotEqual(t, version, put("Changed"))
+	})
+}
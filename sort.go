@@ -0,0 +1,175 @@
+package babyapi
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SortQueryParam is the query parameter used to override the default sort order from WithDefaultSort
+const SortQueryParam = "sort"
+
+type sortField struct {
+	name       string
+	index      []int
+	descending bool
+}
+
+// WithDefaultSort sets the order of resources from the default GetAll handler. The sort is a comma-separated list of
+// JSON field names where a "-" prefix sorts in descending order, like "-created_at,name". Clients can use the sort
+// query parameter with the same format to override the default. Sorting is stable so resources with equal values keep
+// the order from storage
+func (a *API[T]) WithDefaultSort(sort string) *API[T] {
+	a.panicIfReadOnly()
+
+	if a.instance == nil {
+		a.errors = append(a.errors, fmt.Errorf("WithDefaultSort: cannot be used with a root API"))
+		return a
+	}
+
+	_, err := parseSort(reflect.TypeOf(a.instance()), sort)
+	if err != nil {
+		a.errors = append(a.errors, fmt.Errorf("WithDefaultSort: %w", err))
+		return a
+	}
+
+	a.defaultSort = sort
+	return a
+}
+
+// getSortFields parses the sort query parameter, or uses the default sort if it is not set. Sorting is only enabled
+// when WithDefaultSort is used
+func (a *API[T]) getSortFields(r *http.Request) ([]sortField, error) {
+	if a.defaultSort == "" {
+		return nil, nil
+	}
+
+	sort := r.URL.Query().Get(SortQueryParam)
+	if sort == "" {
+		sort = a.defaultSort
+	}
+
+	return parseSort(reflect.TypeOf(a.instance()), sort)
+}
+
+// parseSort uses the resource type to find the fields in a comma-separated sort
+func parseSort(t reflect.Type, sort string) ([]sortField, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unable to sort resource type %s", t)
+	}
+
+	fields := []sortField{}
+	for _, name := range strings.Split(sort, ",") {
+		name = strings.TrimSpace(name)
+
+		descending := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "-"), "+")
+		if name == "" {
+			continue
+		}
+
+		idx := slices.IndexFunc(getJSONFields(t), func(f jsonField) bool { return f.name == name })
+		if idx < 0 {
+			return nil, fmt.Errorf("unsupported sort field %q", name)
+		}
+
+		fields = append(fields, sortField{name, getJSONFields(t)[idx].index, descending})
+	}
+
+	return fields, nil
+}
+
+// sortResources sorts the resources in place by each field in order
+func sortResources[T any](resources []T, fields []sortField) {
+	if len(fields) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(resources, func(a, b T) int {
+		for _, field := range fields {
+			result := compareSortValues(jsonFieldValue(a, field.index), jsonFieldValue(b, field.index))
+			if field.descending {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	})
+}
+
+// jsonFieldValue gets the field at the index from the resource. An invalid Value is returned for nil values
+func jsonFieldValue(resource any, index []int) reflect.Value {
+	v := reflect.ValueOf(resource)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	v, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// compareSortValues compares values of the same field. Nil values are sorted first and types that don't have a
+// natural order are compared by their string representation
+func compareSortValues(a, b reflect.Value) int {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+
+	if a.Kind() != b.Kind() {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(boolToInt(a.Bool()), boolToInt(b.Bool()))
+	}
+
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package babyapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	"github.com/stretchr/testify/require"
+)
+
+type RankedSong struct {
+	babyapi.DefaultResource
+	Title     string    `json:"title"`
+	Rank      *int      `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestDefaultSort(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	songs := []*RankedSong{
+		{Title: "B", Rank: ptr(2), CreatedAt: start},
+		{Title: "C", Rank: nil, CreatedAt: start.Add(2 * time.Hour)},
+		{Title: "A", Rank: ptr(1), CreatedAt: start.Add(time.Hour)},
+		{Title: "D", Rank: ptr(1), CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	api := babyapi.NewAPI("Songs", "/songs", func() *RankedSong { return &RankedSong{} }).
+		WithDefaultSort("-created_at")
+	for _, song := range songs {
+		song.DefaultResource = babyapi.NewDefaultResource()
+		require.NoError(t, api.Storage.Set(context.Background(), song))
+	}
+
+	router, err := api.Router()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"Default", "", []string{"D", "C", "A", "B"}},
+		{"ClientOverride", "?sort=title", []string{"A", "B", "C", "D"}},
+		{"MultipleFields", "?sort=rank,-title", []string{"C", "D", "A", "B"}},
+		{"Descending", "?sort=-rank,created_at", []string{"B", "A", "D", "C"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/songs"+tt.query, http.NoBody))
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+			var resp babyapi.ResourceList[*RankedSong]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			titles := []string{}
+			for _, song := range resp.Items {
+				titles = append(titles, song.Title)
+			}
+			require.Equal(t, tt.expected, titles)
+		})
+	}
+
+	t.Run("UnsupportedField", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/songs?sort=missing", http.NoBody))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		require.Contains(t, w.Body.String(), `unsupported sort field \"missing\"`)
+	})
+
+	t.Run("InvalidDefault", func(t *testing.T) {
+		api := babyapi.NewAPI("Songs", "/songs", func() *RankedSong { return &RankedSong{} }).
+			WithDefaultSort("missing")
+
+		_, err := api.Router()
+		require.ErrorContains(t, err, `WithDefaultSort: unsupported sort field "missing"`)
+	})
+}
@@ -8,6 +8,13 @@ import (
 
 var ErrNotFound = errors.New("resource not found")
 
+// ErrStorageUnavailable can be returned by Storage implementations when the backend can't be reached. FailoverStorage
+// uses it to decide when to try the next Storage
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
+// ErrMissingID is used when a request to get a resource by ID has an empty ID
+var ErrMissingID = errors.New("missing resource ID")
+
 // FilterFunc is used for GetAll to filter resources that are read from storage
 type FilterFunc[T any] func(T) bool
 
@@ -29,12 +36,29 @@ func (f FilterFunc[T]) Filter(in []T) []T {
 
 // Storage defines how the API will interact with a storage backend
 type Storage[T Resource] interface {
-	// Get a single resource by ID
+	// Get a single resource by ID, or by key if the resource implements StorageKeyer
 	Get(context.Context, string) (T, error)
 	// GetAll will return all resources that match the provided query filters
 	GetAll(context.Context, url.Values) ([]T, error)
 	// Set will save the provided resource
 	Set(context.Context, T) error
-	// Delete will delete a resource by ID
+	// Delete will delete a resource by ID, or by key if the resource implements StorageKeyer
 	Delete(context.Context, string) error
 }
+
+// StorageKeyer can be implemented by a Resource to control the key used to store it instead of its ID. This allows
+// namespaced or compound keys without changing the public ID. SetStorageKeyFunc is required so the API can create the
+// same key from the ID in the request URL
+type StorageKeyer interface {
+	StorageKey() string
+}
+
+// StorageKey returns the key used to store a resource. It uses StorageKey if the resource implements StorageKeyer
+// and otherwise falls back to GetID
+func StorageKey[T Resource](resource T) string {
+	keyer, ok := any(resource).(StorageKeyer)
+	if ok {
+		return keyer.StorageKey()
+	}
+	return resource.GetID()
+}
@@ -0,0 +1,372 @@
+package babyapi
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TimeFormatUnix can be used with WithTimeFormat to use Unix timestamps in seconds
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli can be used with WithTimeFormat to use Unix timestamps in milliseconds
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// WithTimeFormat sets the format used for all time.Time fields in JSON requests and responses instead of RFC3339.
+// The format is a layout for time.Format, TimeFormatUnix, or TimeFormatUnixMilli. Zero times are rendered as null.
+// Fields are found using reflection, so this applies to nested structs, slices, and maps without custom types.
+// Nested APIs use the format from their parent unless they set their own
+func (a *API[T]) WithTimeFormat(format string) *API[T] {
+	a.panicIfReadOnly()
+
+	a.timeFormat = format
+	return a
+}
+
+func (a *API[T]) timeFormatMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), timeFormatCtxKey, a.timeFormat)))
+	})
+}
+
+func getTimeFormatFromContext(ctx context.Context) string {
+	format, _ := ctx.Value(timeFormatCtxKey).(string)
+	return format
+}
+
+// formatResponseTimes converts time fields in the JSON data for the response value v to the format from the request
+// context
+func formatResponseTimes(r *http.Request, v, data any) (any, error) {
+	format := getTimeFormatFromContext(r.Context())
+	if format == "" {
+		return data, nil
+	}
+
+	return formatTimes(v, data, format)
+}
+
+// formatTimes converts the RFC3339 time fields in the JSON data for the value v to the format
+func formatTimes(v, data any, format string) (any, error) {
+	value := reflect.ValueOf(v)
+	return convertTimes(value.Type(), value, data, func(data any) (any, error) {
+		s, ok := data.(string)
+		if !ok {
+			return data, nil
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.IsZero() {
+			return nil, nil
+		}
+
+		return formatTime(t, format), nil
+	})
+}
+
+// parseRequestTimes converts time fields in the decoded JSON request body from the format in the request context to
+// RFC3339 so the body can be bound normally
+func parseRequestTimes(r *http.Request, target, data any) (any, error) {
+	format := getTimeFormatFromContext(r.Context())
+	if format == "" {
+		return data, nil
+	}
+
+	data, err := convertTimes(reflect.TypeOf(target), reflect.Value{}, data, func(data any) (any, error) {
+		if data == nil {
+			return nil, nil
+		}
+
+		t, err := parseTime(data, format)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Format(time.RFC3339Nano), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid time: %w", err)
+	}
+
+	return data, nil
+}
+
+func formatTime(t time.Time, format string) any {
+	switch format {
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	default:
+		return t.Format(format)
+	}
+}
+
+func parseTime(data any, format string) (time.Time, error) {
+	switch format {
+	case TimeFormatUnix, TimeFormatUnixMilli:
+		n, ok := data.(json.Number)
+		if !ok {
+			return time.Time{}, fmt.Errorf("expected Unix timestamp but got %v", data)
+		}
+
+		i, err := n.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if format == TimeFormatUnixMilli {
+			return time.UnixMilli(i), nil
+		}
+		return time.Unix(i, 0), nil
+	default:
+		s, ok := data.(string)
+		if !ok {
+			return time.Time{}, fmt.Errorf("expected time string but got %v", data)
+		}
+
+		return time.Parse(format, s)
+	}
+}
+
+func decodeJSONWithNumbers(r io.Reader) (any, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var result any
+	err := decoder.Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// wrappedResponse is implemented by responses that add to the JSON of another value, so the time fields of the
+// underlying value can still be found
+type wrappedResponse interface {
+	unwrapResponse() any
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// convertTimes walks the generic JSON data alongside the Go type it was created from and uses convert to replace
+// the value of every time.Time. The Go value is optional and is used to find the dynamic types of interfaces
+func convertTimes(t reflect.Type, v reflect.Value, data any, convert func(any) (any, error)) (any, error) {
+	t, v, ok := resolveJSONType(t, v)
+	if !ok {
+		return data, nil
+	}
+
+	if t == timeType {
+		return convert(data)
+	}
+
+	if hasCustomJSON(t) {
+		return data, nil
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data, nil
+		}
+
+		for _, field := range getJSONFields(t) {
+			fieldData, ok := m[field.name]
+			if !ok {
+				continue
+			}
+
+			var fieldValue reflect.Value
+			if v.IsValid() {
+				fieldValue, err = v.FieldByIndexErr(field.index)
+				if err != nil {
+					fieldValue = reflect.Value{}
+				}
+			}
+
+			m[field.name], err = convertTimes(field.typ, fieldValue, fieldData, convert)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, ok := data.([]any)
+		if !ok {
+			return data, nil
+		}
+
+		for i := range s {
+			var elemValue reflect.Value
+			if v.IsValid() && i < v.Len() {
+				elemValue = v.Index(i)
+			}
+
+			s[i], err = convertTimes(t.Elem(), elemValue, s[i], convert)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data, nil
+		}
+
+		for key, elemData := range m {
+			var elemValue reflect.Value
+			if v.IsValid() && t.Key().Kind() == reflect.String {
+				elemValue = v.MapIndex(reflect.ValueOf(key).Convert(t.Key()))
+			}
+
+			m[key], err = convertTimes(t.Elem(), elemValue, elemData, convert)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// resolveJSONType dereferences pointers and interfaces and unwraps wrapped responses to find the type that is
+// encoded to JSON. It returns false if the value is nil or the dynamic type of an interface is unknown
+func resolveJSONType(t reflect.Type, v reflect.Value) (reflect.Type, reflect.Value, bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface {
+		if t.Kind() == reflect.Interface {
+			if !v.IsValid() || v.IsNil() {
+				return nil, reflect.Value{}, false
+			}
+			v = v.Elem()
+			t = v.Type()
+			continue
+		}
+
+		if v.IsValid() {
+			if v.IsNil() {
+				return nil, reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		t = t.Elem()
+	}
+
+	if v.IsValid() && v.CanInterface() {
+		wrapped, ok := v.Interface().(wrappedResponse)
+		if ok {
+			inner := reflect.ValueOf(wrapped.unwrapResponse())
+			if !inner.IsValid() {
+				return nil, reflect.Value{}, false
+			}
+			return resolveJSONType(inner.Type(), inner)
+		}
+	}
+
+	return t, v, true
+}
+
+// hasCustomJSON checks if a type controls its own JSON encoding so its fields should not be inspected
+func hasCustomJSON(t reflect.Type) bool {
+	for _, iface := range []reflect.Type{jsonMarshalerType, jsonUnmarshalerType, textMarshalerType, textUnmarshalerType} {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+type jsonField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	tagged bool
+	access string
+}
+
+var jsonFieldsCache sync.Map
+
+// getJSONFields returns the fields of a struct type using their JSON names. Fields from embedded structs are
+// included unless a field with the same name is less nested, which matches encoding/json
+func getJSONFields(t reflect.Type) []jsonField {
+	cached, ok := jsonFieldsCache.Load(t)
+	if ok {
+		return cached.([]jsonField)
+	}
+
+	fields := []jsonField{}
+	collectJSONFields(t, nil, map[string]bool{}, &fields)
+
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, index []int, seen map[string]bool, fields *[]jsonField) {
+	type embeddedField struct {
+		index []int
+		typ   reflect.Type
+	}
+	embedded := []embeddedField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct && !hasCustomJSON(fieldType) {
+				embedded = append(embedded, embeddedField{fieldIndex, fieldType})
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = field.Name
+		}
+
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		*fields = append(*fields, jsonField{name, fieldIndex, field.Type, tagged, field.Tag.Get("babyapi")})
+	}
+
+	for _, e := range embedded {
+		collectJSONFields(e.typ, e.index, seen, fields)
+	}
+}
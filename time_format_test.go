@@ -0,0 +1,149 @@
+package babyapi_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/babyapi"
+	babytest "github.com/calvinmclean/babyapi/test"
+	"github.com/stretchr/testify/require"
+)
+
+type Schedule struct {
+	Times []time.Time `json:"times"`
+}
+
+type Event struct {
+	babyapi.DefaultResource
+	Name     string               `json:"name"`
+	Start    time.Time            `json:"start"`
+	End      *time.Time           `json:"end,omitempty"`
+	Schedule Schedule             `json:"schedule"`
+	Extra    map[string]time.Time `json:"extra,omitempty"`
+}
+
+func TestTimeFormat(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	event := &Event{
+		DefaultResource: babyapi.NewDefaultResource(),
+		Name:            "Event1",
+		Start:           start,
+		End:             &end,
+		Schedule:        Schedule{Times: []time.Time{start, {}}},
+		Extra:           map[string]time.Time{"created": start},
+	}
+	zeroEvent := &Event{DefaultResource: babyapi.NewDefaultResource(), Name: "Event2"}
+
+	tests := []struct {
+		name         string
+		format       string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			"GetUnix",
+			babyapi.TimeFormatUnix,
+			http.MethodGet,
+			"/events/" + event.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + event.GetID() + `","name":"Event1","start":1704164645,"end":1704168245,"schedule":{"times":[1704164645,null]},"extra":{"created":1704164645}}`,
+		},
+		{
+			"GetZeroTimesAreNull",
+			babyapi.TimeFormatUnix,
+			http.MethodGet,
+			"/events/" + zeroEvent.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + zeroEvent.GetID() + `","name":"Event2","start":null,"schedule":{"times":null}}`,
+		},
+		{
+			"GetLayout",
+			time.DateOnly,
+			http.MethodGet,
+			"/events/" + event.GetID(),
+			"",
+			http.StatusOK,
+			`{"id":"` + event.GetID() + `","name":"Event1","start":"2024-01-02","end":"2024-01-02","schedule":{"times":["2024-01-02",null]},"extra":{"created":"2024-01-02"}}`,
+		},
+		{
+			"GetAllUnixMilli",
+			babyapi.TimeFormatUnixMilli,
+			http.MethodGet,
+			"/events?name=Event2",
+			"",
+			http.StatusOK,
+			`{"items":[{"id":"` + zeroEvent.GetID() + `","name":"Event2","start":null,"schedule":{"times":null}}]}`,
+		},
+		{
+			"PutUnix",
+			babyapi.TimeFormatUnix,
+			http.MethodPut,
+			"/events/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Event3","start":1704164645,"end":null,"schedule":{"times":[1704164645]}}`,
+			http.StatusOK,
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Event3","start":1704164645,"schedule":{"times":[1704164645]}}`,
+		},
+		{
+			"PutInvalidTime",
+			babyapi.TimeFormatUnix,
+			http.MethodPut,
+			"/events/cljcqg5o402e9s28rbp0",
+			`{"id":"cljcqg5o402e9s28rbp0","name":"Event3","start":"2024-01-02"}`,
+			http.StatusBadRequest,
+			`{"status":"Invalid request.","error_code":"invalid_request","error":"invalid time: expected Unix timestamp but got 2024-01-02"}`,
+		},
+		{
+			"PostLayout",
+			time.DateOnly,
+			http.MethodPost,
+			"/events",
+			`{"name":"Event4","start":"2024-01-02"}`,
+			http.StatusCreated,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := babyapi.NewAPI("Events", "/events", func() *Event { return &Event{} }).
+				WithTimeFormat(tt.format).
+				SetFilterableFields(babyapi.FilterableField[*Event]{Name: "name", Value: func(e *Event) string { return e.Name }})
+			require.NoError(t, api.Storage.Set(context.Background(), event))
+			require.NoError(t, api.Storage.Set(context.Background(), zeroEvent))
+
+			r, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", "application/json")
+
+			w := babytest.TestRequest[*Event](t, api, r)
+			require.Equal(t, tt.expectedCode, w.Result().StatusCode)
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+
+			if tt.method == http.MethodPost {
+				events, err := api.Storage.GetAll(context.Background(), nil)
+				require.NoError(t, err)
+
+				var created *Event
+				for _, e := range events {
+					if e.Name == "Event4" {
+						created = e
+					}
+				}
+				require.NotNil(t, created)
+				require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), created.Start)
+			}
+		})
+	}
+}